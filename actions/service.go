@@ -0,0 +1,277 @@
+// Package actions provides Invocable Actions API operations.
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/PramithaMJ/salesforce/v2/types"
+)
+
+// MaxBatchSize is the largest number of inputs accepted per invocation request.
+const MaxBatchSize = 2000
+
+// Common custom action types.
+const (
+	TypeFlow = "flow"
+	TypeApex = "apex"
+)
+
+// Common standard actions.
+const (
+	ActionEmailSimple        = "emailSimple"
+	ActionChatterPost        = "chatterPost"
+	ActionCustomNotification = "customNotificationAction"
+	ActionSubmitForApproval  = "submit"
+)
+
+// Action summarizes an available action.
+type Action struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	URL   string `json:"url,omitempty"`
+}
+
+// ActionList contains a list of actions.
+type ActionList struct {
+	Actions []Action `json:"actions"`
+}
+
+// Parameter describes an input or output parameter of an action.
+type Parameter struct {
+	Name           string          `json:"name"`
+	Label          string          `json:"label"`
+	Description    string          `json:"description,omitempty"`
+	Type           string          `json:"type"`
+	SObjectType    string          `json:"sobjectType,omitempty"`
+	Required       bool            `json:"required"`
+	ByteLength     int             `json:"byteLength"`
+	MaxOccurs      int             `json:"maxOccurs"`
+	PicklistValues []PicklistValue `json:"picklistValues,omitempty"`
+}
+
+// PicklistValue represents an allowed parameter value.
+type PicklistValue struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// Description contains the metadata of a single action.
+type Description struct {
+	Name               string      `json:"name"`
+	Label              string      `json:"label"`
+	Description        string      `json:"description,omitempty"`
+	Type               string      `json:"type"`
+	Category           string      `json:"category,omitempty"`
+	Inputs             []Parameter `json:"inputs"`
+	Outputs            []Parameter `json:"outputs"`
+	StandardParameters []Parameter `json:"standardParameters,omitempty"`
+}
+
+// Input returns the input parameter with the given name.
+func (d *Description) Input(name string) *Parameter {
+	for i := range d.Inputs {
+		if d.Inputs[i].Name == name {
+			return &d.Inputs[i]
+		}
+	}
+	return nil
+}
+
+// Error represents an action invocation error.
+type Error struct {
+	StatusCode string   `json:"statusCode"`
+	Message    string   `json:"message"`
+	Fields     []string `json:"fields,omitempty"`
+}
+
+// Result is the outcome of invoking an action for a single input.
+type Result struct {
+	ActionName   string                 `json:"actionName"`
+	IsSuccess    bool                   `json:"isSuccess"`
+	Errors       []Error                `json:"errors,omitempty"`
+	OutputValues map[string]interface{} `json:"outputValues,omitempty"`
+	Version      int                    `json:"version,omitempty"`
+}
+
+// Err returns the invocation errors as an error, or nil on success.
+func (r *Result) Err() error {
+	if r.IsSuccess {
+		return nil
+	}
+	if len(r.Errors) == 0 {
+		return &types.APIError{Message: "action failed", ErrorCode: "UNKNOWN_ERROR"}
+	}
+	errs := make(types.APIErrors, len(r.Errors))
+	for i, e := range r.Errors {
+		errs[i] = types.APIError{Message: e.Message, ErrorCode: types.ErrorCode(e.StatusCode), Fields: e.Fields}
+	}
+	return errs
+}
+
+// InvokeRequest is the request body for invoking an action.
+type InvokeRequest struct {
+	Inputs []map[string]interface{} `json:"inputs"`
+}
+
+// HTTPClient interface for dependency injection.
+type HTTPClient interface {
+	Get(ctx context.Context, path string) ([]byte, error)
+	Post(ctx context.Context, path string, body interface{}) ([]byte, error)
+}
+
+// Service provides Invocable Actions API operations.
+type Service struct {
+	client     HTTPClient
+	apiVersion string
+	batchSize  int
+}
+
+// NewService creates a new Actions service.
+func NewService(client HTTPClient, apiVersion string) *Service {
+	return &Service{client: client, apiVersion: apiVersion, batchSize: MaxBatchSize}
+}
+
+// SetBatchSize sets how many inputs are sent per request, up to MaxBatchSize.
+func (s *Service) SetBatchSize(size int) {
+	if size <= 0 || size > MaxBatchSize {
+		size = MaxBatchSize
+	}
+	s.batchSize = size
+}
+
+// ListStandard lists the standard actions, such as emailSimple and chatterPost.
+func (s *Service) ListStandard(ctx context.Context) ([]Action, error) {
+	path := fmt.Sprintf("/services/data/v%s/actions/standard", s.apiVersion)
+	return s.listActions(ctx, path)
+}
+
+// ListCustomTypes returns the available custom action types and their URLs.
+func (s *Service) ListCustomTypes(ctx context.Context) (map[string]string, error) {
+	path := fmt.Sprintf("/services/data/v%s/actions/custom", s.apiVersion)
+	respBody, err := s.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]string
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return result, nil
+}
+
+// ListCustom lists the custom actions of a type, such as TypeFlow or TypeApex.
+func (s *Service) ListCustom(ctx context.Context, actionType string) ([]Action, error) {
+	path := fmt.Sprintf("/services/data/v%s/actions/custom/%s", s.apiVersion, actionType)
+	return s.listActions(ctx, path)
+}
+
+// DescribeStandard returns the input and output parameters of a standard action.
+func (s *Service) DescribeStandard(ctx context.Context, name string) (*Description, error) {
+	return s.describe(ctx, s.standardPath(name))
+}
+
+// DescribeCustom returns the input and output parameters of a custom action.
+func (s *Service) DescribeCustom(ctx context.Context, actionType, name string) (*Description, error) {
+	return s.describe(ctx, s.customPath(actionType, name))
+}
+
+// InvokeStandard invokes a standard action once per input.
+func (s *Service) InvokeStandard(ctx context.Context, name string, inputs []map[string]interface{}) ([]Result, error) {
+	return s.invoke(ctx, s.standardPath(name), inputs)
+}
+
+// InvokeCustom invokes a custom action, such as an @InvocableMethod, once per input.
+func (s *Service) InvokeCustom(ctx context.Context, actionType, name string, inputs []map[string]interface{}) ([]Result, error) {
+	return s.invoke(ctx, s.customPath(actionType, name), inputs)
+}
+
+// LaunchFlow runs an autolaunched Flow by API name with a single set of inputs.
+func (s *Service) LaunchFlow(ctx context.Context, flowAPIName string, inputs map[string]interface{}) (*Result, error) {
+	if inputs == nil {
+		inputs = map[string]interface{}{}
+	}
+	results, err := s.LaunchFlows(ctx, flowAPIName, []map[string]interface{}{inputs})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("flow %s returned no results", flowAPIName)
+	}
+	return &results[0], results[0].Err()
+}
+
+// LaunchFlows runs an autolaunched Flow once per input.
+func (s *Service) LaunchFlows(ctx context.Context, flowAPIName string, inputs []map[string]interface{}) ([]Result, error) {
+	return s.InvokeCustom(ctx, TypeFlow, flowAPIName, inputs)
+}
+
+func (s *Service) standardPath(name string) string {
+	return fmt.Sprintf("/services/data/v%s/actions/standard/%s", s.apiVersion, url.PathEscape(name))
+}
+
+func (s *Service) customPath(actionType, name string) string {
+	return fmt.Sprintf("/services/data/v%s/actions/custom/%s/%s", s.apiVersion, actionType, url.PathEscape(name))
+}
+
+func (s *Service) listActions(ctx context.Context, path string) ([]Action, error) {
+	respBody, err := s.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var result ActionList
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return result.Actions, nil
+}
+
+func (s *Service) describe(ctx context.Context, path string) (*Description, error) {
+	respBody, err := s.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var desc Description
+	if err := json.Unmarshal(respBody, &desc); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &desc, nil
+}
+
+// invoke sends inputs in batches and returns one result per input, in order.
+func (s *Service) invoke(ctx context.Context, path string, inputs []map[string]interface{}) ([]Result, error) {
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+	results := make([]Result, 0, len(inputs))
+	for start := 0; start < len(inputs); start += s.batchSize {
+		batch := inputs[start:min(start+s.batchSize, len(inputs))]
+		respBody, err := s.client.Post(ctx, path, InvokeRequest{Inputs: batch})
+		if err != nil {
+			return results, err
+		}
+		var batchResults []Result
+		if err := json.Unmarshal(respBody, &batchResults); err != nil {
+			return results, fmt.Errorf("failed to parse response: %w", err)
+		}
+		if len(batchResults) != len(batch) {
+			return results, fmt.Errorf("expected %d results, got %d", len(batch), len(batchResults))
+		}
+		results = append(results, batchResults...)
+	}
+	return results, nil
+}
+
+// FailedResults returns the results that did not succeed, keyed by input index.
+func FailedResults(results []Result) map[int]Result {
+	failed := make(map[int]Result)
+	for i, r := range results {
+		if !r.IsSuccess {
+			failed[i] = r
+		}
+	}
+	return failed
+}
@@ -0,0 +1,391 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	sfhttp "github.com/PramithaMJ/salesforce/v2/http"
+	"github.com/PramithaMJ/salesforce/v2/sobjects"
+	"github.com/PramithaMJ/salesforce/v2/types"
+)
+
+// StreamingHTTPClient is implemented by HTTP clients that can stream
+// response bodies and expose response headers. The SDK's http.Client
+// satisfies it.
+type StreamingHTTPClient interface {
+	Do(ctx context.Context, req sfhttp.Request) (*sfhttp.Response, error)
+}
+
+// ResultKind selects the result set of an ingest job.
+type ResultKind string
+
+const (
+	ResultsSuccessful  ResultKind = "successfulResults"
+	ResultsFailed      ResultKind = "failedResults"
+	ResultsUnprocessed ResultKind = "unprocessedrecords"
+)
+
+// Decoder converts CSV values to typed values using field types from
+// object metadata: booleans become bool, int fields int64, double, currency
+// and percent fields float64, and date, datetime and time fields
+// types.Date, types.DateTime and types.Time. Other fields stay strings.
+// Empty values, which Bulk API uses for null, become nil.
+type Decoder struct {
+	types map[string]string
+}
+
+// NewDecoder creates a decoder. meta may be nil, leaving every value a string.
+func NewDecoder(meta *sobjects.Metadata) *Decoder {
+	d := &Decoder{types: map[string]string{"sf__created": "boolean"}}
+	if meta != nil {
+		for _, f := range meta.Fields {
+			d.types[strings.ToLower(f.Name)] = f.Type
+		}
+	}
+	return d
+}
+
+// SetType sets the field type of a column, such as a relationship column
+// like Account.AnnualRevenue that the object's metadata does not describe.
+func (d *Decoder) SetType(column, fieldType string) {
+	d.types[strings.ToLower(column)] = fieldType
+}
+
+// Decode converts the value of a column.
+func (d *Decoder) Decode(column, raw string) (interface{}, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var (
+		v   interface{}
+		err error
+	)
+	switch d.types[strings.ToLower(column)] {
+	case "boolean":
+		v, err = strconv.ParseBool(raw)
+	case "int":
+		v, err = strconv.ParseInt(raw, 10, 64)
+	case "double", "currency", "percent":
+		v, err = strconv.ParseFloat(raw, 64)
+	case "date":
+		v, err = types.ParseDate(raw)
+	case "datetime":
+		v, err = types.ParseDateTime(raw)
+	case "time":
+		v, err = types.ParseTime(raw)
+	default:
+		return raw, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("column %s: %w", column, err)
+	}
+	return v, nil
+}
+
+// RowReader reads CSV rows one at a time, decoding them into records or
+// structs. It satisfies RecordIterator, so results can be streamed into
+// another job with UploadIterator.
+type RowReader struct {
+	r       *csv.Reader
+	body    io.Closer
+	dec     *Decoder
+	columns []string
+	row     []string
+	record  map[string]interface{}
+	locator string
+	err     error
+	fields  map[reflect.Type]map[string]structField
+}
+
+// NewRowReader creates a RowReader and reads the header line. dec may be
+// nil, leaving every value a string.
+func NewRowReader(r io.Reader, delimiter ColumnDelimiter, dec *Decoder) (*RowReader, error) {
+	if dec == nil {
+		dec = NewDecoder(nil)
+	}
+	cr := csv.NewReader(r)
+	cr.Comma = delimiter.Rune()
+	cr.ReuseRecord = true
+	rr := &RowReader{r: cr, dec: dec}
+	header, err := cr.Read()
+	switch {
+	case err == io.EOF:
+		return rr, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	rr.columns = append([]string(nil), header...)
+	return rr, nil
+}
+
+// Columns returns the CSV columns.
+func (r *RowReader) Columns() []string {
+	return r.columns
+}
+
+// Locator returns the locator of the next page of query results, or ""
+// for the last page. It is only known for readers opened with
+// StreamingHTTPClient support.
+func (r *RowReader) Locator() string {
+	return r.locator
+}
+
+// Next reads the next row. It returns false at the end of the data or on
+// an error.
+func (r *RowReader) Next() bool {
+	if r.err != nil || r.columns == nil {
+		return false
+	}
+	row, err := r.r.Read()
+	if err != nil {
+		if err != io.EOF {
+			r.err = fmt.Errorf("failed to read row: %w", err)
+		}
+		r.row, r.record = nil, nil
+		return false
+	}
+	r.row, r.record = row, nil
+	return true
+}
+
+// Row returns the raw values of the current row. The slice is reused by Next.
+func (r *RowReader) Row() []string {
+	return r.row
+}
+
+// Record returns the current row as a decoded record. A value that cannot
+// be decoded stops iteration with an error.
+func (r *RowReader) Record() map[string]interface{} {
+	if r.record != nil || r.row == nil {
+		return r.record
+	}
+	record := make(map[string]interface{}, len(r.columns))
+	for i, col := range r.columns {
+		v, err := r.dec.Decode(col, r.row[i])
+		if err != nil {
+			r.err = err
+		}
+		record[col] = v
+	}
+	r.record = record
+	return record
+}
+
+// Decode decodes the current row into a struct pointer. Columns are
+// matched case-insensitively to fields using the bulk tags described in
+// StructRecords. Values are parsed according to the Go field type, so no
+// metadata is needed; empty values leave fields at their zero value.
+func (r *RowReader) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Decode expects a pointer to a struct, got %T", v)
+	}
+	rv = rv.Elem()
+	if r.fields == nil {
+		r.fields = make(map[reflect.Type]map[string]structField)
+	}
+	fields, ok := r.fields[rv.Type()]
+	if !ok {
+		fields = make(map[string]structField)
+		for _, f := range structFields(rv.Type(), "", nil) {
+			fields[strings.ToLower(f.column)] = f
+		}
+		r.fields[rv.Type()] = fields
+	}
+	for i, col := range r.columns {
+		f, ok := fields[strings.ToLower(col)]
+		if !ok || r.row[i] == "" {
+			continue
+		}
+		if err := setField(allocFieldByIndex(rv, f.index), r.row[i]); err != nil {
+			return fmt.Errorf("column %s: %w", col, err)
+		}
+	}
+	return nil
+}
+
+// Err returns the error that stopped iteration, if any.
+func (r *RowReader) Err() error {
+	return r.err
+}
+
+// Close closes the underlying response body, if any.
+func (r *RowReader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+	return r.body.Close()
+}
+
+// allocFieldByIndex is reflect.Value.FieldByIndex, allocating nil
+// pointers to nested structs along the way.
+func allocFieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+func setField(v reflect.Value, raw string) error {
+	if v.Kind() == reflect.Pointer {
+		elem := reflect.New(v.Type().Elem())
+		if err := setField(elem.Elem(), raw); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+	switch v.Type() {
+	case timeType:
+		dt, err := types.ParseDateTime(raw)
+		if err != nil {
+			d, derr := types.ParseDate(raw)
+			if derr != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(d.Time))
+			return nil
+		}
+		v.Set(reflect.ValueOf(dt.Time))
+		return nil
+	case dateType:
+		d, err := types.ParseDate(raw)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(d))
+		return nil
+	case dateTimeType:
+		dt, err := types.ParseDateTime(raw)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(dt))
+		return nil
+	case timeOfDay:
+		t, err := types.ParseTime(raw)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("cannot decode into %s", v.Type())
+		}
+		parts := strings.Split(raw, ";")
+		s := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, p := range parts {
+			s.Index(i).SetString(p)
+		}
+		v.Set(s)
+	default:
+		return fmt.Errorf("cannot decode into %s", v.Type())
+	}
+	return nil
+}
+
+// QueryResultsOptions configures OpenQueryResults.
+type QueryResultsOptions struct {
+	// Locator selects a page of results. Empty for the first page.
+	Locator string
+	// MaxRecords is the most rows returned in the page.
+	MaxRecords int
+	// Delimiter is the job's column delimiter. When empty it is read from
+	// the job, which the Service fetches at most once per job.
+	Delimiter ColumnDelimiter
+	// Decoder converts values. Defaults to leaving them strings.
+	Decoder *Decoder
+}
+
+// OpenQueryResults opens one page of query job results for row-by-row
+// reading. With a StreamingHTTPClient the response is streamed rather than
+// held in memory; the caller must Close the reader.
+func (s *Service) OpenQueryResults(ctx context.Context, jobID string, opts *QueryResultsOptions) (*RowReader, error) {
+	if opts == nil {
+		opts = &QueryResultsOptions{}
+	}
+	delimiter := opts.Delimiter
+	if delimiter == "" {
+		var err error
+		if delimiter, err = s.queryDelimiter(ctx, jobID); err != nil {
+			return nil, err
+		}
+	}
+	path := s.queryResultsPath(jobID, opts.MaxRecords, opts.Locator)
+	return s.openCSV(ctx, path, delimiter, opts.Decoder)
+}
+
+// OpenIngestResults opens the successful, failed or unprocessed records
+// of an ingest job for row-by-row reading. The sf__Created column decodes
+// as a bool. The caller must Close the reader.
+func (s *Service) OpenIngestResults(ctx context.Context, jobID string, kind ResultKind, delimiter ColumnDelimiter, dec *Decoder) (*RowReader, error) {
+	path := fmt.Sprintf("/services/data/v%s/jobs/ingest/%s/%s", s.apiVersion, jobID, kind)
+	return s.openCSV(ctx, path, delimiter, dec)
+}
+
+func (s *Service) openCSV(ctx context.Context, path string, delimiter ColumnDelimiter, dec *Decoder) (*RowReader, error) {
+	sc, ok := s.client.(StreamingHTTPClient)
+	if !ok {
+		respBody, err := s.client.Get(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		return NewRowReader(bytes.NewReader(respBody), delimiter, dec)
+	}
+	resp, err := sc.Do(ctx, sfhttp.Request{Method: http.MethodGet, Path: path, Accept: "text/csv"})
+	if err != nil {
+		return nil, err
+	}
+	rr, err := NewRowReader(resp.Body, delimiter, dec)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	rr.body = resp.Body
+	if locator := resp.Header.Get("Sforce-Locator"); locator != "null" {
+		rr.locator = locator
+	}
+	return rr, nil
+}
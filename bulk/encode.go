@@ -0,0 +1,363 @@
+package bulk
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PramithaMJ/salesforce/v2/sobjects"
+	"github.com/PramithaMJ/salesforce/v2/types"
+)
+
+// NullValue sets a field to null in Bulk API CSV data. An empty value
+// leaves the field unchanged.
+const NullValue = "#N/A"
+
+// Bulk API CSV layouts of date, datetime and time values.
+const (
+	CSVDateLayout     = "2006-01-02"
+	CSVDateTimeLayout = "2006-01-02T15:04:05.000Z"
+	CSVTimeLayout     = "15:04:05.000Z"
+)
+
+// Encoder formats record values for Bulk API CSV data. Numbers are written
+// without exponents, booleans as true or false, times in UTC, zero times
+// like nil and multi-select picklist slices joined with semicolons. With
+// object metadata, time.Time values of date and time fields are written as
+// dates and times rather than datetimes.
+type Encoder struct {
+	// NilAsNull writes nil values as NullValue, setting the field to null,
+	// instead of leaving them empty.
+	NilAsNull bool
+
+	fields map[string]*sobjects.FieldMetadata
+}
+
+// NewEncoder creates an encoder. meta may be nil.
+func NewEncoder(meta *sobjects.Metadata) *Encoder {
+	e := &Encoder{fields: make(map[string]*sobjects.FieldMetadata)}
+	if meta != nil {
+		for i := range meta.Fields {
+			e.fields[strings.ToLower(meta.Fields[i].Name)] = &meta.Fields[i]
+		}
+	}
+	return e
+}
+
+var defaultEncoder = NewEncoder(nil)
+
+// Format formats the value of a column.
+func (e *Encoder) Format(column string, v interface{}) (string, error) {
+	var fieldType string
+	if f := e.fields[strings.ToLower(column)]; f != nil {
+		fieldType = f.Type
+	}
+	return e.format(fieldType, v)
+}
+
+func (e *Encoder) format(fieldType string, v interface{}) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		if e.NilAsNull {
+			return NullValue, nil
+		}
+		return "", nil
+	case string:
+		return val, nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32), nil
+	case json.Number:
+		return val.String(), nil
+	case time.Time:
+		if val.IsZero() {
+			return e.format(fieldType, nil)
+		}
+		switch fieldType {
+		case "date":
+			return val.Format(CSVDateLayout), nil
+		case "time":
+			return val.UTC().Format(CSVTimeLayout), nil
+		}
+		return val.UTC().Format(CSVDateTimeLayout), nil
+	case types.Date:
+		if val.IsZero() {
+			return e.format(fieldType, nil)
+		}
+		return val.Format(CSVDateLayout), nil
+	case types.DateTime:
+		if val.IsZero() {
+			return e.format(fieldType, nil)
+		}
+		return val.UTC().Format(CSVDateTimeLayout), nil
+	case types.Time:
+		if val.IsZero() {
+			return e.format(fieldType, nil)
+		}
+		return val.UTC().Format(CSVTimeLayout), nil
+	case types.ID:
+		return string(val), nil
+	case []string:
+		return strings.Join(val, ";"), nil
+	case fmt.Stringer:
+		return val.String(), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return e.format(fieldType, nil)
+		}
+		return e.format(fieldType, rv.Elem().Interface())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.String {
+			values := make([]string, rv.Len())
+			for i := range values {
+				values[i] = rv.Index(i).String()
+			}
+			return strings.Join(values, ";"), nil
+		}
+	}
+	return "", fmt.Errorf("cannot encode %T as a CSV value", v)
+}
+
+// value returns a record's value for a column. Relationship columns such
+// as Account.External_Id__c are read from flat keys or nested maps.
+func value(record map[string]interface{}, column string) interface{} {
+	if v, ok := record[column]; ok {
+		return v
+	}
+	dot := strings.Index(column, ".")
+	if dot < 0 {
+		return nil
+	}
+	nested, ok := record[column[:dot]].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return value(nested, column[dot+1:])
+}
+
+// recordColumns returns the sorted columns of records, naming values in
+// nested maps by their dotted path.
+func recordColumns(records ...map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var columns []string
+	var add func(prefix string, record map[string]interface{})
+	add = func(prefix string, record map[string]interface{}) {
+		for key, v := range record {
+			if nested, ok := v.(map[string]interface{}); ok {
+				add(prefix+key+".", nested)
+				continue
+			}
+			if !seen[prefix+key] {
+				seen[prefix+key] = true
+				columns = append(columns, prefix+key)
+			}
+		}
+	}
+	for _, record := range records {
+		add("", record)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// StructRecords converts a slice of structs, or of pointers to structs,
+// into records, returning them with their columns in field order. Fields
+// are named by their bulk tag, or by their Go name without one:
+//
+//	type Contact struct {
+//		LastName  string
+//		Email     *string    `bulk:"Email,null"`
+//		Birthdate time.Time  `bulk:"Birthdate"`
+//		AccountID string     `bulk:"Account.External_Id__c"`
+//		Internal  string     `bulk:"-"`
+//	}
+//
+// The null option writes NullValue, setting the field to null, for values
+// that would otherwise be written empty and leave the field unchanged: nil
+// pointers, empty strings and slices, and zero times. Booleans and numbers
+// are always written as they are, so false and 0 are not nulls. A nested
+// struct field names a relationship whose fields become columns such as
+// Account.External_Id__c, while the fields of an untagged embedded struct
+// are promoted as in encoding/json. Nil elements are an error.
+func StructRecords(v interface{}) ([]map[string]interface{}, []string, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil, nil, fmt.Errorf("StructRecords expects a slice, got %T", v)
+	}
+	elem := rv.Type().Elem()
+	if elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("StructRecords expects a slice of structs, got %T", v)
+	}
+	fields := structFields(elem, "", nil)
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = f.column
+	}
+	records := make([]map[string]interface{}, rv.Len())
+	for i := range records {
+		item := rv.Index(i)
+		if item.Kind() == reflect.Pointer {
+			if item.IsNil() {
+				return nil, nil, fmt.Errorf("StructRecords: element %d is nil", i)
+			}
+			item = item.Elem()
+		}
+		records[i] = structRecord(item, fields)
+	}
+	return records, columns, nil
+}
+
+// StructRecord converts a struct, or a pointer to one, into a record. See
+// StructRecords.
+func StructRecord(v interface{}) (map[string]interface{}, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("StructRecord expects a struct, got %T", v)
+	}
+	return structRecord(rv, structFields(rv.Type(), "", nil)), nil
+}
+
+type structField struct {
+	column string
+	index  []int
+	null   bool
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	dateType     = reflect.TypeOf(types.Date{})
+	dateTimeType = reflect.TypeOf(types.DateTime{})
+	timeOfDay    = reflect.TypeOf(types.Time{})
+)
+
+// structFields returns the columns of a struct type. A promoted field is
+// dropped when the outer struct has a column of the same name.
+func structFields(t reflect.Type, prefix string, index []int) []structField {
+	var fields []structField
+	promoted := make(map[int]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("bulk")
+		if tag == "-" {
+			continue
+		}
+		idx := append(append([]int(nil), index...), i)
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		isStruct := ft.Kind() == reflect.Struct && ft != timeType && ft != dateType && ft != dateTimeType && ft != timeOfDay
+		if f.Anonymous && tag == "" && isStruct && (f.IsExported() || f.Type.Kind() != reflect.Pointer) {
+			for _, pf := range structFields(ft, prefix, idx) {
+				promoted[len(fields)] = true
+				fields = append(fields, pf)
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+		if isStruct {
+			fields = append(fields, structFields(ft, prefix+name+".", idx)...)
+			continue
+		}
+		fields = append(fields, structField{column: prefix + name, index: idx, null: opts == "null"})
+	}
+	if len(promoted) == 0 {
+		return fields
+	}
+	outer := make(map[string]bool)
+	for i, f := range fields {
+		if !promoted[i] {
+			outer[f.column] = true
+		}
+	}
+	kept := fields[:0]
+	for i, f := range fields {
+		if !promoted[i] || !outer[f.column] {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+func structRecord(v reflect.Value, fields []structField) map[string]interface{} {
+	record := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		fv, ok := fieldByIndex(v, f.index)
+		switch {
+		case !ok || (fv.Kind() == reflect.Pointer && fv.IsNil()):
+			record[f.column] = nil
+		case fv.Kind() == reflect.Pointer:
+			record[f.column] = fv.Elem().Interface()
+		default:
+			record[f.column] = fv.Interface()
+		}
+		if f.null && (!ok || isEmpty(fv)) {
+			record[f.column] = NullValue
+		}
+	}
+	return record
+}
+
+// isEmpty reports whether a field value is written as an empty CSV value.
+func isEmpty(v reflect.Value) bool {
+	if v.Kind() == reflect.Pointer {
+		return v.IsNil() || isEmpty(v.Elem())
+	}
+	switch v.Type() {
+	case timeType, dateType, dateTimeType, timeOfDay:
+		return v.IsZero()
+	}
+	switch v.Kind() {
+	case reflect.String, reflect.Slice:
+		return v.Len() == 0
+	}
+	return false
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex, returning false at a nil
+// pointer instead of panicking.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
@@ -0,0 +1,64 @@
+package bulk
+
+import (
+	"context"
+
+	"github.com/PramithaMJ/salesforce/v2/export"
+)
+
+// ExportOptions configures ExportQueryResults.
+type ExportOptions struct {
+	// Columns are written as the header when the job's results are empty
+	// and have no header line of their own. Writers that need a schema,
+	// such as export.ParquetWriter, fail on such results without them.
+	Columns []string
+}
+
+// ExportQueryResults writes the results of a completed query job to w,
+// returning the number of rows written. Columns are the job's CSV columns,
+// which already use dotted relationship paths such as Account.Name, and
+// empty values are written as nil. Results without rows write just the
+// header. opts may be nil. ExportQueryResults closes w.
+func (s *Service) ExportQueryResults(ctx context.Context, jobID string, w export.Writer, opts *ExportOptions) (count int, err error) {
+	defer func() {
+		if cerr := w.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	it := s.IterateQueryResults(ctx, jobID, nil)
+	defer it.Close()
+	var values []interface{}
+	for it.Next() {
+		if values == nil {
+			if err := w.WriteHeader(it.Columns()); err != nil {
+				return count, err
+			}
+			values = make([]interface{}, len(it.Columns()))
+		}
+		for i, v := range it.Row() {
+			values[i] = nil
+			if v != "" {
+				values[i] = v
+			}
+		}
+		if err := w.WriteRow(values); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := it.Err(); err != nil {
+		return count, err
+	}
+	if values == nil {
+		columns := it.Columns()
+		if columns == nil && opts != nil {
+			columns = opts.Columns
+		}
+		if len(columns) > 0 {
+			if err := w.WriteHeader(columns); err != nil {
+				return count, err
+			}
+		}
+	}
+	return count, nil
+}
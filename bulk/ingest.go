@@ -0,0 +1,309 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Upload limits of a single Bulk API 2.0 ingest job. Salesforce's 150 MB
+// limit applies to the data after base64 encoding, so MaxJobBytes is the
+// raw CSV size that encodes to it.
+const (
+	MaxJobBytes      = 150 * 1000 * 1000 / 4 * 3
+	MaxJobCharacters = 100 * 1000 * 1000
+)
+
+// IngestOptions configures Ingest.
+type IngestOptions struct {
+	// Columns are the CSV columns to upload. Defaults to every key found in
+	// the records, sorted.
+	Columns []string
+	// ExternalIDField is the external ID field used by upserts.
+	ExternalIDField string
+	// KeyField is the column used to match job results to input records,
+	// such as Id for updates or an external ID for upserts. It must be one
+	// of the uploaded columns. It is shorthand for a Key that returns the
+	// column's value.
+	KeyField string
+	// Key returns the correlation key of a row from its uploaded column
+	// values: for input records, the values as encoded for upload, and for
+	// result records, the values Salesforce returns. Use it when no single
+	// column identifies a record, or when Salesforce returns a value in a
+	// different form than it was uploaded. It overrides KeyField. When
+	// neither is set, rows are matched on the values of all uploaded
+	// columns, so records identical in every column receive their results
+	// in input order.
+	Key func(values map[string]string) string
+	// MaxBytes is the most raw CSV data uploaded to one job. Defaults to
+	// MaxJobBytes.
+	MaxBytes int
+	// MaxCharacters is the most CSV characters uploaded to one job.
+	// Defaults to MaxJobCharacters.
+	MaxCharacters int
+	// PollInterval is how often job states are checked.
+	PollInterval time.Duration
+	// Encoder formats values. Defaults to an encoder without metadata.
+	Encoder *Encoder
+}
+
+// RowResult is the outcome of one input record.
+type RowResult struct {
+	// Index is the record's position in the input.
+	Index int
+	// Key is the record's correlation key, if KeyField or Key is set.
+	Key   string
+	JobID string
+	// ID is the ID of the created, updated or deleted record.
+	ID      string
+	Created bool
+	Success bool
+	// Error is the Salesforce error for failed records, or the job's
+	// error for unprocessed ones.
+	Error string
+	// Unprocessed is true if the job stopped before processing the record.
+	// A record missing from every result set is neither successful nor
+	// unprocessed, and has an Error saying so.
+	Unprocessed bool
+}
+
+// IngestResult contains the jobs run by Ingest and the outcome of each
+// input record, in input order.
+type IngestResult struct {
+	Jobs []*JobInfo
+	Rows []RowResult
+}
+
+// Failed returns the rows that failed or were not processed.
+func (r *IngestResult) Failed() []RowResult {
+	var failed []RowResult
+	for _, row := range r.Rows {
+		if !row.Success {
+			failed = append(failed, row)
+		}
+	}
+	return failed
+}
+
+// Ingest runs a complete ingest operation: it splits the records into as
+// many jobs as the upload limits require, uploads and closes each job,
+// waits for all of them and matches their successful, failed and
+// unprocessed results back to the input records. Jobs that fail as a
+// whole leave their records unprocessed rather than returning an error.
+// With the SDK's HTTP client each job's data is uploaded in one request,
+// without retries and without the client's Timeout; use ctx to bound it.
+func (s *Service) Ingest(ctx context.Context, object string, op Operation, records []map[string]interface{}, opts *IngestOptions) (*IngestResult, error) {
+	if opts == nil {
+		opts = &IngestOptions{}
+	}
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = recordColumns(records...)
+	}
+	enc := opts.Encoder
+	if enc == nil {
+		enc = defaultEncoder
+	}
+	key := opts.Key
+	if key == nil && opts.KeyField != "" {
+		found := false
+		for _, col := range columns {
+			found = found || col == opts.KeyField
+		}
+		if !found {
+			return nil, fmt.Errorf("key field %s is not an uploaded column", opts.KeyField)
+		}
+		field := opts.KeyField
+		key = func(values map[string]string) string { return values[field] }
+	}
+	c := &correlator{columns: columns, key: key}
+
+	chunks, err := splitCSV(records, columns, enc, c, opts.MaxBytes, opts.MaxCharacters)
+	if err != nil {
+		return nil, err
+	}
+	result := &IngestResult{Rows: make([]RowResult, len(records))}
+	for _, chunk := range chunks {
+		job, err := s.CreateJob(ctx, CreateJobRequest{
+			Object:              object,
+			Operation:           op,
+			ExternalIdFieldName: opts.ExternalIDField,
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Jobs = append(result.Jobs, job)
+		if err := s.uploadStream(ctx, job.ID, bytes.NewReader(chunk.data)); err != nil {
+			s.AbortJob(ctx, job.ID)
+			return result, err
+		}
+		if _, err := s.CloseJob(ctx, job.ID); err != nil {
+			return result, err
+		}
+		chunk.jobID = job.ID
+	}
+
+	for i, chunk := range chunks {
+		job, err := s.WaitForCompletion(ctx, chunk.jobID, opts.PollInterval)
+		if err != nil {
+			return result, err
+		}
+		result.Jobs[i] = job
+		if err := s.collectResults(ctx, job, chunk, c, result.Rows); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// ingestChunk is the CSV data of one job, the input rows it holds and
+// their correlation keys.
+type ingestChunk struct {
+	jobID string
+	data  []byte
+	rows  []int
+	keys  []string
+}
+
+// splitCSV encodes records as CSV, starting a new chunk, with its own
+// header, whenever the next row would exceed a limit.
+func splitCSV(records []map[string]interface{}, columns []string, encoder *Encoder, c *correlator, maxBytes, maxChars int) ([]*ingestChunk, error) {
+	if maxBytes <= 0 {
+		maxBytes = MaxJobBytes
+	}
+	if maxChars <= 0 {
+		maxChars = MaxJobCharacters
+	}
+	enc := newCSVEncoder(DelimiterComma, LineEndingLF)
+	header, err := enc.encode(columns)
+	if err != nil {
+		return nil, err
+	}
+	header = append([]byte(nil), header...)
+	var chunks []*ingestChunk
+	var current *ingestChunk
+	chars := 0
+	values := make([]string, len(columns))
+	for i, record := range records {
+		for j, col := range columns {
+			if values[j], err = encoder.Format(col, value(record, col)); err != nil {
+				return nil, fmt.Errorf("record %d, column %s: %w", i, col, err)
+			}
+		}
+		line, err := enc.encode(values)
+		if err != nil {
+			return nil, err
+		}
+		lineChars := utf8.RuneCount(line)
+		if current == nil || len(current.data)+len(line) > maxBytes || chars+lineChars > maxChars {
+			if len(header)+len(line) > maxBytes || utf8.RuneCount(header)+lineChars > maxChars {
+				return nil, fmt.Errorf("record %d is larger than the job upload limit", i)
+			}
+			current = &ingestChunk{data: append([]byte(nil), header...)}
+			chars = utf8.RuneCount(header)
+			chunks = append(chunks, current)
+		}
+		current.data = append(current.data, line...)
+		current.rows = append(current.rows, i)
+		current.keys = append(current.keys, c.keyOf(values))
+		chars += lineChars
+	}
+	return chunks, nil
+}
+
+// correlator computes the keys that match result records to input rows.
+type correlator struct {
+	columns []string
+	key     func(values map[string]string) string
+}
+
+// keyOf returns the key of a row's values, in column order. Without a key
+// function it is all values joined.
+func (c *correlator) keyOf(values []string) string {
+	if c.key == nil {
+		return strings.Join(values, "\x00")
+	}
+	byColumn := make(map[string]string, len(c.columns))
+	for i, col := range c.columns {
+		byColumn[col] = values[i]
+	}
+	return c.key(byColumn)
+}
+
+// resultKey returns the key of a result record.
+func (c *correlator) resultKey(data map[string]interface{}) string {
+	values := make([]string, len(c.columns))
+	for i, col := range c.columns {
+		values[i] = getString(data, col)
+	}
+	return c.keyOf(values)
+}
+
+// collectResults matches a job's successful, failed and unprocessed
+// records to its input rows by correlation key. Rows of an aborted job are
+// all unprocessed; rows missing from every result set are reported as
+// such.
+func (s *Service) collectResults(ctx context.Context, job *JobInfo, chunk *ingestChunk, c *correlator, rows []RowResult) error {
+	pending := make(map[string][]int)
+	for i, index := range chunk.rows {
+		key := chunk.keys[i]
+		pending[key] = append(pending[key], index)
+		rows[index] = RowResult{Index: index, JobID: chunk.jobID, Unprocessed: true, Error: job.ErrorMessage}
+		if c.key != nil {
+			rows[index].Key = key
+		}
+	}
+	if job.State == StateAborted {
+		return nil
+	}
+	for _, indexes := range pending {
+		for _, index := range indexes {
+			rows[index].Unprocessed = false
+			rows[index].Error = "record not found in the job's results"
+		}
+	}
+	match := func(data map[string]interface{}) (int, bool) {
+		key := c.resultKey(data)
+		indexes := pending[key]
+		if len(indexes) == 0 {
+			return 0, false
+		}
+		pending[key] = indexes[1:]
+		return indexes[0], true
+	}
+
+	successes, err := s.GetSuccessfulRecords(ctx, chunk.jobID)
+	if err != nil {
+		return err
+	}
+	for _, r := range successes {
+		if index, ok := match(r.Data); ok {
+			row := &rows[index]
+			row.ID, row.Created, row.Success, row.Error = r.ID, r.Created, true, ""
+		}
+	}
+	failures, err := s.GetFailedRecords(ctx, chunk.jobID)
+	if err != nil {
+		return err
+	}
+	for _, r := range failures {
+		if index, ok := match(r.Data); ok {
+			row := &rows[index]
+			row.ID, row.Error = r.ID, r.Error
+		}
+	}
+	unprocessed, err := s.GetUnprocessedRecords(ctx, chunk.jobID)
+	if err != nil {
+		return err
+	}
+	for _, data := range unprocessed {
+		if index, ok := match(data); ok {
+			row := &rows[index]
+			row.Unprocessed, row.Error = true, job.ErrorMessage
+		}
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+package bulk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/PramithaMJ/salesforce/v2/types"
+)
+
+// JobType represents the API a job was created with.
+type JobType string
+
+const (
+	JobTypeBigObjectIngest JobType = "BigObjectIngest"
+	JobTypeClassic         JobType = "Classic"
+	JobTypeV2Ingest        JobType = "V2Ingest"
+	JobTypeV2Query         JobType = "V2Query"
+)
+
+// JobKind selects ingest jobs, query jobs or both.
+type JobKind string
+
+const (
+	JobKindAll    JobKind = ""
+	JobKindIngest JobKind = "ingest"
+	JobKindQuery  JobKind = "query"
+)
+
+// JobListOptions filters job lists. Empty fields do not filter.
+type JobListOptions struct {
+	// IsPkChunkingEnabled selects jobs with or without PK chunking.
+	IsPkChunkingEnabled *bool
+	// JobType selects jobs created with one API.
+	JobType JobType
+	// ConcurrencyMode selects jobs by concurrency mode, such as Parallel.
+	ConcurrencyMode string
+	// QueryLocator starts the list at a page returned in NextRecordsURL.
+	QueryLocator string
+}
+
+// QueryJobListResult contains a list of query jobs.
+type QueryJobListResult struct {
+	Done           bool           `json:"done"`
+	Records        []QueryJobInfo `json:"records"`
+	NextRecordsURL string         `json:"nextRecordsUrl,omitempty"`
+}
+
+func (s *Service) jobsPath(kind JobKind, opts *JobListOptions) string {
+	path := fmt.Sprintf("/services/data/v%s/jobs/%s", s.apiVersion, kind)
+	if opts == nil {
+		return path
+	}
+	params := url.Values{}
+	if opts.IsPkChunkingEnabled != nil {
+		params.Set("isPkChunkingEnabled", strconv.FormatBool(*opts.IsPkChunkingEnabled))
+	}
+	if opts.JobType != "" {
+		params.Set("jobType", string(opts.JobType))
+	}
+	if opts.ConcurrencyMode != "" {
+		params.Set("concurrencyMode", opts.ConcurrencyMode)
+	}
+	if opts.QueryLocator != "" {
+		params.Set("queryLocator", opts.QueryLocator)
+	}
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	return path
+}
+
+func (s *Service) listPage(ctx context.Context, path string, v interface{}) error {
+	respBody, err := s.client.Get(ctx, path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(respBody, v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// ListIngestJobs lists one page of ingest jobs.
+func (s *Service) ListIngestJobs(ctx context.Context, opts *JobListOptions) (*JobListResult, error) {
+	var result JobListResult
+	if err := s.listPage(ctx, s.jobsPath(JobKindIngest, opts), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListQueryJobs lists one page of query jobs.
+func (s *Service) ListQueryJobs(ctx context.Context, opts *JobListOptions) (*QueryJobListResult, error) {
+	var result QueryJobListResult
+	if err := s.listPage(ctx, s.jobsPath(JobKindQuery, opts), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// JobIterator walks every page of an ingest job list, following
+// NextRecordsURL.
+type JobIterator struct {
+	svc   *Service
+	ctx   context.Context
+	path  string
+	jobs  []JobInfo
+	index int
+	job   *JobInfo
+	err   error
+}
+
+// IterateJobs returns an iterator over the ingest jobs matching opts.
+func (s *Service) IterateJobs(ctx context.Context, opts *JobListOptions) *JobIterator {
+	return &JobIterator{svc: s, ctx: ctx, path: s.jobsPath(JobKindIngest, opts)}
+}
+
+// Next advances to the next job. It returns false when the list is
+// exhausted or an error occurs.
+func (it *JobIterator) Next() bool {
+	for it.index >= len(it.jobs) {
+		it.job = nil
+		if it.path == "" || it.err != nil {
+			return false
+		}
+		var page JobListResult
+		if err := it.svc.listPage(it.ctx, it.path, &page); err != nil {
+			it.err = err
+			return false
+		}
+		it.jobs, it.index, it.path = page.Records, 0, ""
+		if !page.Done {
+			it.path = page.NextRecordsURL
+		}
+	}
+	it.job = &it.jobs[it.index]
+	it.index++
+	return true
+}
+
+// Job returns the current job.
+func (it *JobIterator) Job() *JobInfo {
+	return it.job
+}
+
+// Err returns the error that stopped iteration, if any.
+func (it *JobIterator) Err() error {
+	return it.err
+}
+
+// QueryJobIterator walks every page of a query job list, following
+// NextRecordsURL.
+type QueryJobIterator struct {
+	svc   *Service
+	ctx   context.Context
+	path  string
+	jobs  []QueryJobInfo
+	index int
+	job   *QueryJobInfo
+	err   error
+}
+
+// IterateQueryJobs returns an iterator over the query jobs matching opts.
+func (s *Service) IterateQueryJobs(ctx context.Context, opts *JobListOptions) *QueryJobIterator {
+	return &QueryJobIterator{svc: s, ctx: ctx, path: s.jobsPath(JobKindQuery, opts)}
+}
+
+// Next advances to the next job. It returns false when the list is
+// exhausted or an error occurs.
+func (it *QueryJobIterator) Next() bool {
+	for it.index >= len(it.jobs) {
+		it.job = nil
+		if it.path == "" || it.err != nil {
+			return false
+		}
+		var page QueryJobListResult
+		if err := it.svc.listPage(it.ctx, it.path, &page); err != nil {
+			it.err = err
+			return false
+		}
+		it.jobs, it.index, it.path = page.Records, 0, ""
+		if !page.Done {
+			it.path = page.NextRecordsURL
+		}
+	}
+	it.job = &it.jobs[it.index]
+	it.index++
+	return true
+}
+
+// Job returns the current job.
+func (it *QueryJobIterator) Job() *QueryJobInfo {
+	return it.job
+}
+
+// Err returns the error that stopped iteration, if any.
+func (it *QueryJobIterator) Err() error {
+	return it.err
+}
+
+// CleanupOptions configures CleanupJobs.
+type CleanupOptions struct {
+	// OlderThan is the minimum age of a job, from its CreatedDate. Required.
+	OlderThan time.Duration
+	// States selects jobs in these states. Empty selects every state.
+	States []State
+	// Kind selects ingest jobs, query jobs or both.
+	Kind JobKind
+	// Filter filters the job lists.
+	Filter *JobListOptions
+	// Delete deletes the selected jobs, after aborting those that are
+	// still open or running. Otherwise unfinished jobs are only aborted.
+	Delete bool
+	// DryRun reports the selected jobs without changing them.
+	DryRun bool
+}
+
+// CleanedJob is a job selected by CleanupJobs and what was done to it.
+type CleanedJob struct {
+	ID          string
+	Kind        JobKind
+	State       State
+	CreatedDate string
+	Aborted     bool
+	Deleted     bool
+	// Err is the error aborting or deleting the job, if any.
+	Err error
+}
+
+// CleanupJobs aborts, and optionally deletes, jobs that are older than
+// opts.OlderThan and in one of opts.States. Jobs that cannot be aborted or
+// deleted are reported with their error rather than stopping the cleanup;
+// the returned error is only set if listing jobs fails.
+func (s *Service) CleanupJobs(ctx context.Context, opts CleanupOptions) ([]CleanedJob, error) {
+	if opts.OlderThan <= 0 {
+		return nil, fmt.Errorf("OlderThan must be positive")
+	}
+	cutoff := time.Now().Add(-opts.OlderThan)
+	selected := func(state State, created string) bool {
+		if len(opts.States) > 0 && !hasState(opts.States, state) {
+			return false
+		}
+		t, err := types.ParseDateTime(created)
+		return err == nil && t.Before(cutoff)
+	}
+
+	var jobs []CleanedJob
+	if opts.Kind != JobKindQuery {
+		it := s.IterateJobs(ctx, opts.Filter)
+		for it.Next() {
+			if job := it.Job(); selected(job.State, job.CreatedDate) {
+				jobs = append(jobs, CleanedJob{ID: job.ID, Kind: JobKindIngest, State: job.State, CreatedDate: job.CreatedDate})
+			}
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Kind != JobKindIngest {
+		it := s.IterateQueryJobs(ctx, opts.Filter)
+		for it.Next() {
+			if job := it.Job(); selected(job.State, job.CreatedDate) {
+				jobs = append(jobs, CleanedJob{ID: job.ID, Kind: JobKindQuery, State: job.State, CreatedDate: job.CreatedDate})
+			}
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if opts.DryRun {
+		return jobs, nil
+	}
+
+	for i := range jobs {
+		job := &jobs[i]
+		if job.State == StateOpen || job.State == StateUploadComplete || job.State == StateInProgress {
+			if job.Kind == JobKindQuery {
+				_, job.Err = s.AbortQueryJob(ctx, job.ID)
+			} else {
+				_, job.Err = s.AbortJob(ctx, job.ID)
+			}
+			if job.Err != nil {
+				continue
+			}
+			job.Aborted = true
+		}
+		if opts.Delete {
+			if job.Kind == JobKindQuery {
+				job.Err = s.DeleteQueryJob(ctx, job.ID)
+			} else {
+				job.Err = s.DeleteJob(ctx, job.ID)
+			}
+			job.Deleted = job.Err == nil
+		}
+	}
+	return jobs, nil
+}
+
+func hasState(states []State, state State) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,269 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// DefaultDownloadConcurrency is how many result pages DownloadQueryResults
+// fetches at once by default.
+const DefaultDownloadConcurrency = 4
+
+// QueryResultIterator reads every row of a query job's results, opening
+// the next page by its locator when the current one is exhausted. It
+// satisfies RecordIterator. Always call Close, typically with defer.
+type QueryResultIterator struct {
+	svc   *Service
+	ctx   context.Context
+	jobID string
+	opts  QueryResultsOptions
+	rows  *RowReader
+	pages int
+	err   error
+	done  bool
+}
+
+// IterateQueryResults returns an iterator over the results of a completed
+// query job, starting at opts.Locator. opts.MaxRecords limits the rows of
+// each page, not the total. Following locators requires an HTTP client
+// that implements StreamingHTTPClient; with other clients only the first
+// page is read.
+func (s *Service) IterateQueryResults(ctx context.Context, jobID string, opts *QueryResultsOptions) *QueryResultIterator {
+	o := QueryResultsOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	return &QueryResultIterator{svc: s, ctx: ctx, jobID: jobID, opts: o}
+}
+
+// Next advances to the next row. It returns false when the results are
+// exhausted or an error occurs.
+func (it *QueryResultIterator) Next() bool {
+	for !it.done && it.err == nil {
+		if it.rows != nil {
+			if it.rows.Next() {
+				return true
+			}
+			if err := it.rows.Err(); err != nil {
+				it.err = err
+				break
+			}
+			it.rows.Close()
+			if it.rows.Locator() == "" {
+				it.done = true
+				break
+			}
+			it.opts.Locator = it.rows.Locator()
+		}
+		if it.opts.Delimiter == "" {
+			delimiter, err := it.svc.queryDelimiter(it.ctx, it.jobID)
+			if err != nil {
+				it.err = err
+				break
+			}
+			it.opts.Delimiter = delimiter
+		}
+		rows, err := it.svc.OpenQueryResults(it.ctx, it.jobID, &it.opts)
+		if err != nil {
+			it.err = err
+			break
+		}
+		it.rows = rows
+		it.pages++
+	}
+	return false
+}
+
+// Columns returns the CSV columns. They are known once Next has been called.
+func (it *QueryResultIterator) Columns() []string {
+	if it.rows == nil {
+		return nil
+	}
+	return it.rows.Columns()
+}
+
+// Row returns the raw values of the current row. The slice is reused by Next.
+func (it *QueryResultIterator) Row() []string {
+	return it.rows.Row()
+}
+
+// Record returns the current row as a record decoded by opts.Decoder.
+func (it *QueryResultIterator) Record() map[string]interface{} {
+	record := it.rows.Record()
+	if err := it.rows.Err(); err != nil && it.err == nil {
+		it.err = err
+	}
+	return record
+}
+
+// Decode decodes the current row into a struct pointer. See RowReader.Decode.
+func (it *QueryResultIterator) Decode(v interface{}) error {
+	return it.rows.Decode(v)
+}
+
+// Pages returns the number of result pages opened so far.
+func (it *QueryResultIterator) Pages() int {
+	return it.pages
+}
+
+// Err returns the error that stopped iteration, if any.
+func (it *QueryResultIterator) Err() error {
+	return it.err
+}
+
+// Close closes the current page. It is safe to call more than once.
+func (it *QueryResultIterator) Close() error {
+	it.done = true
+	if it.rows == nil {
+		return nil
+	}
+	return it.rows.Close()
+}
+
+// ResultPage is one page of query job results listed by GetResultPages.
+type ResultPage struct {
+	// ResultLink is the path of the page's results, including its locator.
+	ResultLink string `json:"resultLink"`
+}
+
+// ResultPageList is a list of result pages.
+type ResultPageList struct {
+	ResultPages    []ResultPage `json:"resultPages"`
+	Done           bool         `json:"done"`
+	NextRecordsURL string       `json:"nextRecordsUrl,omitempty"`
+}
+
+// GetResultPages lists every result page of a completed query job, so
+// pages can be downloaded in parallel instead of following locators one
+// after another. maxRecords sets the rows per page; 0 lets Salesforce
+// choose. This uses the resultPages resource, which requires an API
+// version that supports it.
+func (s *Service) GetResultPages(ctx context.Context, jobID string, maxRecords int) ([]ResultPage, error) {
+	path := fmt.Sprintf("/services/data/v%s/jobs/query/%s/resultPages", s.apiVersion, jobID)
+	if maxRecords > 0 {
+		path += "?" + url.Values{"maxRecords": {strconv.Itoa(maxRecords)}}.Encode()
+	}
+	var pages []ResultPage
+	for path != "" {
+		respBody, err := s.client.Get(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		var list ResultPageList
+		if err := json.Unmarshal(respBody, &list); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		pages = append(pages, list.ResultPages...)
+		path = ""
+		if !list.Done {
+			path = list.NextRecordsURL
+		}
+	}
+	return pages, nil
+}
+
+// DownloadOptions configures DownloadQueryResults.
+type DownloadOptions struct {
+	// MaxRecords is the number of rows per result page. 0 lets Salesforce choose.
+	MaxRecords int
+	// Concurrency is how many pages are downloaded at once. Defaults to
+	// DefaultDownloadConcurrency.
+	Concurrency int
+}
+
+type downloadedPage struct {
+	data []byte
+	err  error
+}
+
+// DownloadQueryResults downloads the results of a completed query job to
+// w as a single CSV file, in the job's column delimiter and line ending.
+// Result pages are fetched concurrently with GetResultPages and written in
+// order, with the header line of the first page only; at most Concurrency
+// pages are held in memory.
+func (s *Service) DownloadQueryResults(ctx context.Context, jobID string, w io.Writer, opts *DownloadOptions) error {
+	o := DownloadOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultDownloadConcurrency
+	}
+	pages, err := s.GetResultPages(ctx, jobID, o.MaxRecords)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make([]chan downloadedPage, len(pages))
+	for i := range results {
+		results[i] = make(chan downloadedPage, 1)
+	}
+	slots := make(chan struct{}, o.Concurrency)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i, page := range pages {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			wg.Add(1)
+			go func(i int, link string) {
+				defer wg.Done()
+				data, err := s.client.Get(ctx, link)
+				results[i] <- downloadedPage{data: data, err: err}
+			}(i, page.ResultLink)
+		}
+	}()
+
+	for i := range pages {
+		var page downloadedPage
+		select {
+		case page = <-results[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if page.err != nil {
+			return page.err
+		}
+		data := page.data
+		if i > 0 {
+			if nl := bytes.IndexByte(data, '\n'); nl >= 0 {
+				data = data[nl+1:]
+			} else {
+				data = nil
+			}
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		<-slots
+	}
+	return nil
+}
+
+// DownloadQueryResultsToFile downloads the results of a completed query
+// job to a CSV file, creating or truncating it. See DownloadQueryResults.
+func (s *Service) DownloadQueryResultsToFile(ctx context.Context, jobID, name string, opts *DownloadOptions) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := s.DownloadQueryResults(ctx, jobID, f, opts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
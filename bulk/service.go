@@ -8,6 +8,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/url"
+	"strconv"
+	"sync"
 	"time"
 )
 
@@ -86,6 +89,7 @@ type JobInfo struct {
 	CreatedDate             string      `json:"createdDate"`
 	SystemModstamp          string      `json:"systemModstamp"`
 	ConcurrencyMode         string      `json:"concurrencyMode"`
+	JobType                 JobType     `json:"jobType,omitempty"`
 	ContentURL              string      `json:"contentUrl,omitempty"`
 	NumberRecordsProcessed  int         `json:"numberRecordsProcessed"`
 	NumberRecordsFailed     int         `json:"numberRecordsFailed"`
@@ -122,22 +126,34 @@ type SuccessRecord struct {
 
 // QueryJobRequest contains query job creation parameters.
 type QueryJobRequest struct {
-	Query       string      `json:"query"`
-	Operation   Operation   `json:"operation,omitempty"`
-	ContentType ContentType `json:"contentType,omitempty"`
+	Query           string          `json:"query"`
+	Operation       Operation       `json:"operation,omitempty"`
+	ContentType     ContentType     `json:"contentType,omitempty"`
+	ColumnDelimiter ColumnDelimiter `json:"columnDelimiter,omitempty"`
+	LineEnding      LineEnding      `json:"lineEnding,omitempty"`
 }
 
 // QueryJobInfo contains query job information.
 type QueryJobInfo struct {
-	ID                     string      `json:"id"`
-	Operation              Operation   `json:"operation"`
-	Object                 string      `json:"object"`
-	State                  State       `json:"state"`
-	ContentType            ContentType `json:"contentType"`
-	CreatedById            string      `json:"createdById"`
-	CreatedDate            string      `json:"createdDate"`
-	SystemModstamp         string      `json:"systemModstamp"`
-	NumberRecordsProcessed int         `json:"numberRecordsProcessed"`
+	ID                     string          `json:"id"`
+	Operation              Operation       `json:"operation"`
+	Object                 string          `json:"object"`
+	State                  State           `json:"state"`
+	ContentType            ContentType     `json:"contentType"`
+	ColumnDelimiter        ColumnDelimiter `json:"columnDelimiter"`
+	LineEnding             LineEnding      `json:"lineEnding"`
+	CreatedById            string          `json:"createdById"`
+	CreatedDate            string          `json:"createdDate"`
+	SystemModstamp         string          `json:"systemModstamp"`
+	ConcurrencyMode        string          `json:"concurrencyMode"`
+	JobType                JobType         `json:"jobType,omitempty"`
+	NumberRecordsProcessed int             `json:"numberRecordsProcessed"`
+	ErrorMessage           string          `json:"errorMessage,omitempty"`
+}
+
+// IsComplete returns true if the query job has finished.
+func (j *QueryJobInfo) IsComplete() bool {
+	return j.State == StateJobComplete || j.State == StateFailed || j.State == StateAborted
 }
 
 // JobListResult contains a list of jobs.
@@ -160,6 +176,9 @@ type HTTPClient interface {
 type Service struct {
 	client     HTTPClient
 	apiVersion string
+	// delimiters caches the column delimiter of each query job seen, by
+	// job ID, so reading results page by page does not fetch the job again.
+	delimiters sync.Map
 }
 
 // NewService creates a new Bulk service.
@@ -202,9 +221,7 @@ func (s *Service) UploadCSV(ctx context.Context, jobID string, records []map[str
 	var buf bytes.Buffer
 	writer := csv.NewWriter(&buf)
 	if len(columns) == 0 {
-		for key := range records[0] {
-			columns = append(columns, key)
-		}
+		columns = recordColumns(records[0])
 	}
 	if err := writer.Write(columns); err != nil {
 		return fmt.Errorf("failed to write header: %w", err)
@@ -212,9 +229,11 @@ func (s *Service) UploadCSV(ctx context.Context, jobID string, records []map[str
 	for _, record := range records {
 		row := make([]string, len(columns))
 		for i, col := range columns {
-			if val, ok := record[col]; ok {
-				row[i] = fmt.Sprintf("%v", val)
+			val, err := defaultEncoder.Format(col, value(record, col))
+			if err != nil {
+				return fmt.Errorf("failed to write row: %w", err)
 			}
+			row[i] = val
 		}
 		if err := writer.Write(row); err != nil {
 			return fmt.Errorf("failed to write row: %w", err)
@@ -252,18 +271,19 @@ func (s *Service) GetJob(ctx context.Context, jobID string) (*JobInfo, error) {
 	return &job, nil
 }
 
-// ListJobs lists all ingest jobs.
+// ListJobs lists ingest jobs, filtered by concurrency mode if it is not
+// empty and to PK chunking jobs if isPkChunkingEnabled is true. A false
+// isPkChunkingEnabled does not filter, so jobs without PK chunking cannot
+// be selected. It returns the first page.
+//
+// Deprecated: Use ListIngestJobs, whose JobListOptions.IsPkChunkingEnabled
+// can filter either way, or IterateJobs for every page.
 func (s *Service) ListJobs(ctx context.Context, concurrencyMode string, isPkChunkingEnabled bool) (*JobListResult, error) {
-	path := fmt.Sprintf("/services/data/v%s/jobs/ingest", s.apiVersion)
-	respBody, err := s.client.Get(ctx, path)
-	if err != nil {
-		return nil, err
+	opts := &JobListOptions{ConcurrencyMode: concurrencyMode}
+	if isPkChunkingEnabled {
+		opts.IsPkChunkingEnabled = &isPkChunkingEnabled
 	}
-	var result JobListResult
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-	return &result, nil
+	return s.ListIngestJobs(ctx, opts)
 }
 
 // AbortJob aborts an ingest job.
@@ -381,6 +401,7 @@ func (s *Service) CreateQueryJob(ctx context.Context, req QueryJobRequest) (*Que
 	if err := json.Unmarshal(respBody, &job); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+	s.rememberDelimiter(&job)
 	return &job, nil
 }
 
@@ -395,30 +416,121 @@ func (s *Service) GetQueryJob(ctx context.Context, jobID string) (*QueryJobInfo,
 	if err := json.Unmarshal(respBody, &job); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+	s.rememberDelimiter(&job)
 	return &job, nil
 }
 
-// GetQueryResults retrieves query job results.
-func (s *Service) GetQueryResults(ctx context.Context, jobID string, maxRecords int, locator string) ([]map[string]interface{}, string, error) {
-	path := fmt.Sprintf("/services/data/v%s/jobs/query/%s/results", s.apiVersion, jobID)
-	if maxRecords > 0 || locator != "" {
-		path += "?"
-		if maxRecords > 0 {
-			path += fmt.Sprintf("maxRecords=%d", maxRecords)
-		}
-		if locator != "" {
-			if maxRecords > 0 {
-				path += "&"
+// WaitForQueryCompletion waits for a query job to complete.
+func (s *Service) WaitForQueryCompletion(ctx context.Context, jobID string, pollInterval time.Duration) (*QueryJobInfo, error) {
+	if pollInterval == 0 {
+		pollInterval = 5 * time.Second
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+			job, err := s.GetQueryJob(ctx, jobID)
+			if err != nil {
+				return nil, err
+			}
+			if job.IsComplete() {
+				return job, nil
 			}
-			path += fmt.Sprintf("locator=%s", locator)
 		}
 	}
-	respBody, err := s.client.Get(ctx, path)
+}
+
+// GetQueryResults retrieves a page of query job results. It returns the
+// locator of the next page, or "" after the last one. Locators are read
+// from the Sforce-Locator response header, so clients that do not
+// implement StreamingHTTPClient only see the first page. Rows are parsed
+// with the job's column delimiter.
+func (s *Service) GetQueryResults(ctx context.Context, jobID string, maxRecords int, locator string) ([]map[string]interface{}, string, error) {
+	delimiter, err := s.queryDelimiter(ctx, jobID)
 	if err != nil {
 		return nil, "", err
 	}
-	records, err := parseCSV(respBody)
-	return records, "", err
+	columns, rows, next, err := s.queryResultsPage(ctx, jobID, maxRecords, locator, delimiter)
+	if err != nil {
+		return nil, "", err
+	}
+	records := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		record := make(map[string]interface{})
+		for i, h := range columns {
+			if i < len(row) {
+				record[h] = row[i]
+			}
+		}
+		records = append(records, record)
+	}
+	return records, next, nil
+}
+
+// queryResultsPage reads one page of query job results as CSV columns and
+// rows, returning the locator of the next page, or "" for the last page.
+func (s *Service) queryResultsPage(ctx context.Context, jobID string, maxRecords int, locator string, delimiter ColumnDelimiter) ([]string, [][]string, string, error) {
+	rr, err := s.openCSV(ctx, s.queryResultsPath(jobID, maxRecords, locator), delimiter, nil)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	defer rr.Close()
+	var rows [][]string
+	for rr.Next() {
+		rows = append(rows, append([]string(nil), rr.Row()...))
+	}
+	if err := rr.Err(); err != nil {
+		return nil, nil, "", err
+	}
+	return rr.Columns(), rows, rr.Locator(), nil
+}
+
+// queryDelimiter returns the column delimiter of a query job's results.
+// The job is only fetched if it has not been created or fetched through s
+// before.
+func (s *Service) queryDelimiter(ctx context.Context, jobID string) (ColumnDelimiter, error) {
+	if d, ok := s.delimiters.Load(jobID); ok {
+		return d.(ColumnDelimiter), nil
+	}
+	job, err := s.GetQueryJob(ctx, jobID)
+	if err != nil {
+		return "", err
+	}
+	if job.ColumnDelimiter == "" {
+		return DelimiterComma, nil
+	}
+	return job.ColumnDelimiter, nil
+}
+
+// rememberDelimiter caches a query job's column delimiter. A job's
+// delimiter cannot change, so the cache never goes stale.
+func (s *Service) rememberDelimiter(job *QueryJobInfo) {
+	if job.ID == "" {
+		return
+	}
+	d := job.ColumnDelimiter
+	if d == "" {
+		d = DelimiterComma
+	}
+	s.delimiters.Store(job.ID, d)
+}
+
+func (s *Service) queryResultsPath(jobID string, maxRecords int, locator string) string {
+	path := fmt.Sprintf("/services/data/v%s/jobs/query/%s/results", s.apiVersion, jobID)
+	params := url.Values{}
+	if maxRecords > 0 {
+		params.Set("maxRecords", strconv.Itoa(maxRecords))
+	}
+	if locator != "" {
+		params.Set("locator", locator)
+	}
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	return path
 }
 
 // AbortQueryJob aborts a query job.
@@ -439,6 +551,9 @@ func (s *Service) AbortQueryJob(ctx context.Context, jobID string) (*QueryJobInf
 func (s *Service) DeleteQueryJob(ctx context.Context, jobID string) error {
 	path := fmt.Sprintf("/services/data/v%s/jobs/query/%s", s.apiVersion, jobID)
 	_, err := s.client.Delete(ctx, path)
+	if err == nil {
+		s.delimiters.Delete(jobID)
+	}
 	return err
 }
 
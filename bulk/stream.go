@@ -0,0 +1,341 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"unicode/utf8"
+
+	sfhttp "github.com/PramithaMJ/salesforce/v2/http"
+)
+
+// Rune returns the delimiter character, or ',' for an unknown delimiter.
+func (d ColumnDelimiter) Rune() rune {
+	switch d {
+	case DelimiterTab:
+		return '\t'
+	case DelimiterSemicolon:
+		return ';'
+	case DelimiterPipe:
+		return '|'
+	case DelimiterBackquote:
+		return '`'
+	case DelimiterCaret:
+		return '^'
+	}
+	return ','
+}
+
+// csvEncoder encodes CSV lines with a job's delimiter and line ending.
+type csvEncoder struct {
+	buf bytes.Buffer
+	w   *csv.Writer
+}
+
+func newCSVEncoder(delimiter ColumnDelimiter, lineEnding LineEnding) *csvEncoder {
+	e := &csvEncoder{}
+	e.w = csv.NewWriter(&e.buf)
+	e.w.Comma = delimiter.Rune()
+	e.w.UseCRLF = lineEnding == LineEndingCRLF
+	return e
+}
+
+// encode returns one encoded line. The slice is valid until the next call.
+func (e *csvEncoder) encode(values []string) ([]byte, error) {
+	e.buf.Reset()
+	if err := e.w.Write(values); err != nil {
+		return nil, fmt.Errorf("failed to write row: %w", err)
+	}
+	e.w.Flush()
+	if err := e.w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write row: %w", err)
+	}
+	return e.buf.Bytes(), nil
+}
+
+// RecordIterator yields records one at a time, such as rows read from a
+// database cursor.
+type RecordIterator interface {
+	Next() bool
+	Record() map[string]interface{}
+	Err() error
+}
+
+// StreamOptions configures a StreamWriter.
+type StreamOptions struct {
+	// Columns are the CSV columns. Defaults to the sorted keys of the
+	// first record, with nested maps as relationship columns.
+	Columns []string
+	// MaxBytes is the most raw CSV data uploaded to one job. Defaults to
+	// MaxJobBytes.
+	MaxBytes int
+	// MaxCharacters is the most CSV characters uploaded to one job.
+	// Defaults to MaxJobCharacters.
+	MaxCharacters int
+	// Encoder formats values. Defaults to an encoder without metadata.
+	Encoder *Encoder
+}
+
+// StreamWriter uploads records to ingest jobs as they are written, without
+// holding them in memory. Each job's data is streamed in a single upload
+// request; when the next record would exceed a job's size limits, the job
+// is closed and a new one with the same settings is started. Records are
+// encoded with the job's ColumnDelimiter and LineEnding.
+//
+// With the SDK's HTTP client, uploads are not retried and are not bounded
+// by the client's Timeout, so records may be written as slowly as the
+// source produces them; use ctx to bound the upload. Other clients upload
+// with UploadData, whose retries and timeout apply.
+type StreamWriter struct {
+	svc      *Service
+	ctx      context.Context
+	req      CreateJobRequest
+	opts     StreamOptions
+	enc      *csvEncoder
+	columns  []string
+	header   []byte
+	values   []string
+	job      *JobInfo
+	pipe     *io.PipeWriter
+	uploaded chan error
+	bytes    int
+	chars    int
+	jobs     []*JobInfo
+	err      error
+}
+
+// NewStreamWriter creates a StreamWriter. Jobs are created as records are
+// written, so an unused writer creates none.
+func (s *Service) NewStreamWriter(ctx context.Context, req CreateJobRequest, opts *StreamOptions) *StreamWriter {
+	o := StreamOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.MaxBytes <= 0 {
+		o.MaxBytes = MaxJobBytes
+	}
+	if o.MaxCharacters <= 0 {
+		o.MaxCharacters = MaxJobCharacters
+	}
+	if o.Encoder == nil {
+		o.Encoder = defaultEncoder
+	}
+	if req.ContentType == "" {
+		req.ContentType = ContentTypeCSV
+	}
+	if req.LineEnding == "" {
+		req.LineEnding = LineEndingLF
+	}
+	if req.ColumnDelimiter == "" {
+		req.ColumnDelimiter = DelimiterComma
+	}
+	return &StreamWriter{
+		svc:  s,
+		ctx:  ctx,
+		req:  req,
+		opts: o,
+		enc:  newCSVEncoder(req.ColumnDelimiter, req.LineEnding),
+	}
+}
+
+// Write encodes and uploads one record. After an error the current job is
+// aborted and every later call returns the same error.
+func (w *StreamWriter) Write(record map[string]interface{}) error {
+	if w.err != nil {
+		return w.err
+	}
+	if err := w.write(record); err != nil {
+		w.fail(err)
+		return err
+	}
+	return nil
+}
+
+func (w *StreamWriter) write(record map[string]interface{}) error {
+	if w.columns == nil {
+		if err := w.setColumns(record); err != nil {
+			return err
+		}
+	}
+	for i, col := range w.columns {
+		v, err := w.opts.Encoder.Format(col, value(record, col))
+		if err != nil {
+			return fmt.Errorf("column %s: %w", col, err)
+		}
+		w.values[i] = v
+	}
+	line, err := w.enc.encode(w.values)
+	if err != nil {
+		return err
+	}
+	lineChars := utf8.RuneCount(line)
+	if w.job == nil || w.bytes+len(line) > w.opts.MaxBytes || w.chars+lineChars > w.opts.MaxCharacters {
+		if len(w.header)+len(line) > w.opts.MaxBytes || utf8.RuneCount(w.header)+lineChars > w.opts.MaxCharacters {
+			return fmt.Errorf("record is larger than the job upload limit")
+		}
+		if err := w.finishJob(); err != nil {
+			return err
+		}
+		if err := w.startJob(); err != nil {
+			return err
+		}
+	}
+	if _, err := w.pipe.Write(line); err != nil {
+		return err
+	}
+	w.bytes += len(line)
+	w.chars += lineChars
+	return nil
+}
+
+func (w *StreamWriter) setColumns(record map[string]interface{}) error {
+	columns := w.opts.Columns
+	if len(columns) == 0 {
+		columns = recordColumns(record)
+	}
+	header, err := w.enc.encode(columns)
+	if err != nil {
+		return err
+	}
+	w.columns = columns
+	w.header = append([]byte(nil), header...)
+	w.values = make([]string, len(columns))
+	return nil
+}
+
+func (w *StreamWriter) startJob() error {
+	job, err := w.svc.CreateJob(w.ctx, w.req)
+	if err != nil {
+		return err
+	}
+	pr, pw := io.Pipe()
+	uploaded := make(chan error, 1)
+	go func() {
+		err := w.svc.uploadStream(w.ctx, job.ID, pr)
+		pr.CloseWithError(err)
+		uploaded <- err
+	}()
+	w.job, w.pipe, w.uploaded = job, pw, uploaded
+	w.jobs = append(w.jobs, job)
+	w.bytes, w.chars = 0, 0
+	if _, err := pw.Write(w.header); err != nil {
+		return err
+	}
+	w.bytes += len(w.header)
+	w.chars += utf8.RuneCount(w.header)
+	return nil
+}
+
+// uploadStream uploads a job's data from a one-shot stream. With a
+// StreamingHTTPClient the request is sent once, without retries that would
+// resend a consumed body, and without the HTTP client's overall timeout, so
+// it lasts as long as the producer does; ctx still bounds it. Other
+// clients fall back to UploadData.
+func (s *Service) uploadStream(ctx context.Context, jobID string, data io.Reader) error {
+	sc, ok := s.client.(StreamingHTTPClient)
+	if !ok {
+		return s.UploadData(ctx, jobID, data)
+	}
+	resp, err := sc.Do(ctx, sfhttp.Request{
+		Method:      http.MethodPut,
+		Path:        fmt.Sprintf("/services/data/v%s/jobs/ingest/%s/batches", s.apiVersion, jobID),
+		Body:        data,
+		ContentType: "text/csv",
+		NoTimeout:   true,
+	})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// finishJob ends the current job's upload and closes the job. The upload
+// result is consumed here, so the job is cleared before returning and is
+// aborted by finishJob itself if the upload or close fails.
+func (w *StreamWriter) finishJob() error {
+	if w.job == nil {
+		return nil
+	}
+	current := w.job
+	w.job = nil
+	w.pipe.Close()
+	if err := <-w.uploaded; err != nil {
+		w.svc.AbortJob(w.ctx, current.ID)
+		return err
+	}
+	job, err := w.svc.CloseJob(w.ctx, current.ID)
+	if err != nil {
+		w.svc.AbortJob(w.ctx, current.ID)
+		return err
+	}
+	w.jobs[len(w.jobs)-1] = job
+	return nil
+}
+
+func (w *StreamWriter) fail(err error) {
+	w.err = err
+	if w.job == nil {
+		return
+	}
+	w.pipe.CloseWithError(err)
+	<-w.uploaded
+	w.svc.AbortJob(w.ctx, w.job.ID)
+	w.job = nil
+}
+
+// Close finishes the current job and returns every job the writer
+// created, closed and queued for processing.
+func (w *StreamWriter) Close() ([]*JobInfo, error) {
+	if w.err != nil {
+		return w.jobs, w.err
+	}
+	if err := w.finishJob(); err != nil {
+		w.fail(err)
+		return w.jobs, err
+	}
+	return w.jobs, nil
+}
+
+// Jobs returns the jobs created so far.
+func (w *StreamWriter) Jobs() []*JobInfo {
+	return w.jobs
+}
+
+// UploadStream uploads records received from a channel until it is closed,
+// returning the jobs created. See StreamWriter.
+func (s *Service) UploadStream(ctx context.Context, req CreateJobRequest, records <-chan map[string]interface{}, opts *StreamOptions) ([]*JobInfo, error) {
+	w := s.NewStreamWriter(ctx, req, opts)
+	for {
+		select {
+		case <-ctx.Done():
+			w.fail(ctx.Err())
+			return w.Close()
+		case record, ok := <-records:
+			if !ok {
+				return w.Close()
+			}
+			if err := w.Write(record); err != nil {
+				return w.jobs, err
+			}
+		}
+	}
+}
+
+// UploadIterator uploads every record of an iterator, returning the jobs
+// created. See StreamWriter.
+func (s *Service) UploadIterator(ctx context.Context, req CreateJobRequest, it RecordIterator, opts *StreamOptions) ([]*JobInfo, error) {
+	w := s.NewStreamWriter(ctx, req, opts)
+	for it.Next() {
+		if err := w.Write(it.Record()); err != nil {
+			return w.jobs, err
+		}
+	}
+	if err := it.Err(); err != nil {
+		w.fail(err)
+		return w.jobs, err
+	}
+	return w.Close()
+}
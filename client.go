@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/PramithaMJ/salesforce/v2/actions"
 	"github.com/PramithaMJ/salesforce/v2/analytics"
 	"github.com/PramithaMJ/salesforce/v2/apex"
 	"github.com/PramithaMJ/salesforce/v2/auth"
@@ -15,6 +16,7 @@ import (
 	"github.com/PramithaMJ/salesforce/v2/connect"
 	sfhttp "github.com/PramithaMJ/salesforce/v2/http"
 	"github.com/PramithaMJ/salesforce/v2/limits"
+	"github.com/PramithaMJ/salesforce/v2/process"
 	"github.com/PramithaMJ/salesforce/v2/query"
 	"github.com/PramithaMJ/salesforce/v2/search"
 	"github.com/PramithaMJ/salesforce/v2/sobjects"
@@ -41,6 +43,8 @@ type Client struct {
 	uiapi     *uiapi.Service
 	search    *search.Service
 	apex      *apex.Service
+	actions   *actions.Service
+	process   *process.Service
 }
 
 // NewClient creates a new Salesforce client with the given options.
@@ -122,6 +126,8 @@ func (c *Client) initServices() {
 	c.uiapi = uiapi.NewService(c.httpClient, apiVersion)
 	c.search = search.NewService(c.httpClient, apiVersion)
 	c.apex = apex.NewService(c.httpClient)
+	c.actions = actions.NewService(c.httpClient, apiVersion)
+	c.process = process.NewService(c.httpClient, apiVersion)
 }
 
 // Services access methods
@@ -159,6 +165,12 @@ func (c *Client) Search() *search.Service { return c.search }
 // Apex returns the Apex REST service.
 func (c *Client) Apex() *apex.Service { return c.apex }
 
+// Actions returns the Invocable Actions service.
+func (c *Client) Actions() *actions.Service { return c.actions }
+
+// Process returns the Approval Process and Workflow Rules service.
+func (c *Client) Process() *process.Service { return c.process }
+
 // GetToken returns the current access token.
 func (c *Client) GetToken() *types.Token { return c.auth.GetToken() }
 
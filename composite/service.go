@@ -274,7 +274,9 @@ func (s *Service) DeleteCollection(ctx context.Context, ids []string, allOrNone
 	return resp, nil
 }
 
-// GetCollection retrieves multiple records using SObject Collections.
+// GetCollection retrieves multiple records using SObject Collections. IDs
+// and fields are sent in the URL, which limits the request to a few hundred
+// IDs; see RetrieveCollection for larger requests.
 func (s *Service) GetCollection(ctx context.Context, objectType string, ids []string, fields []string) ([]map[string]interface{}, error) {
 	path := fmt.Sprintf("/services/data/v%s/composite/sobjects/%s?ids=%s",
 		s.apiVersion, objectType, joinIDs(ids))
@@ -292,6 +294,26 @@ func (s *Service) GetCollection(ctx context.Context, objectType string, ids []st
 	return resp, nil
 }
 
+// MaxRetrieveIDs is the largest number of IDs RetrieveCollection accepts.
+const MaxRetrieveIDs = 2000
+
+// RetrieveCollection retrieves up to MaxRetrieveIDs records of one type
+// using the POST form of SObject Collections, which sends the IDs and
+// fields in the request body rather than the URL. fields is required.
+func (s *Service) RetrieveCollection(ctx context.Context, objectType string, ids []string, fields []string) ([]map[string]interface{}, error) {
+	path := fmt.Sprintf("/services/data/v%s/composite/sobjects/%s", s.apiVersion, objectType)
+	req := map[string][]string{"ids": ids, "fields": fields}
+	respBody, err := s.client.Post(ctx, path, req)
+	if err != nil {
+		return nil, err
+	}
+	var resp []map[string]interface{}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return resp, nil
+}
+
 func joinIDs(ids []string) string {
 	result := ""
 	for i, id := range ids {
@@ -0,0 +1,52 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// CSVWriter writes rows as CSV with a header line.
+type CSVWriter struct {
+	w       *csv.Writer
+	columns []string
+	record  []string
+}
+
+// NewCSVWriter creates a CSV writer.
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+// SetDelimiter changes the field delimiter from the default comma.
+func (c *CSVWriter) SetDelimiter(r rune) {
+	c.w.Comma = r
+}
+
+// SetCRLF makes lines end with \r\n instead of \n.
+func (c *CSVWriter) SetCRLF(crlf bool) {
+	c.w.UseCRLF = crlf
+}
+
+// WriteHeader writes the header line.
+func (c *CSVWriter) WriteHeader(columns []string) error {
+	c.columns = columns
+	c.record = make([]string, len(columns))
+	return c.w.Write(columns)
+}
+
+// WriteRow writes one line.
+func (c *CSVWriter) WriteRow(values []interface{}) error {
+	if err := checkRow(c.columns, values); err != nil {
+		return err
+	}
+	for i, v := range values {
+		c.record[i] = FormatValue(v)
+	}
+	return c.w.Write(c.record)
+}
+
+// Close flushes buffered output.
+func (c *CSVWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,90 @@
+// Package export writes tabular rows as CSV, JSON Lines or Parquet. It is
+// used by the query and bulk packages to export query results.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Format is an output file format.
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatJSONL   Format = "jsonl"
+	FormatParquet Format = "parquet"
+)
+
+// Writer writes rows with a fixed set of columns.
+type Writer interface {
+	// WriteHeader sets the columns. It must be called once, before WriteRow.
+	WriteHeader(columns []string) error
+	// WriteRow writes one row, with values in column order. Nil values are
+	// written as empty CSV fields or nulls.
+	WriteRow(values []interface{}) error
+	// Close flushes buffered rows and finishes the output. It does not close
+	// the underlying io.Writer.
+	Close() error
+}
+
+// NewWriter creates a writer for a format.
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatJSONL:
+		return NewJSONLWriter(w), nil
+	case FormatParquet:
+		return NewParquetWriter(w), nil
+	}
+	return nil, fmt.Errorf("unsupported export format: %s", format)
+}
+
+// FormatValue formats a value as text. Numbers use the shortest exact
+// representation, times use RFC 3339 and maps and slices, such as compound
+// fields and child records, are encoded as JSON.
+func FormatValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32)
+	case int:
+		return strconv.Itoa(val)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case json.Number:
+		return val.String()
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return val.String()
+	case map[string]interface{}, []interface{}, []map[string]interface{}:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprintf("%v", val)
+		}
+		return string(data)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func checkRow(columns []string, values []interface{}) error {
+	if columns == nil {
+		return fmt.Errorf("WriteHeader must be called before WriteRow")
+	}
+	if len(values) != len(columns) {
+		return fmt.Errorf("row has %d values, expected %d", len(values), len(columns))
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONLWriter writes rows as JSON Lines, one object per row with keys in
+// column order.
+type JSONLWriter struct {
+	w       io.Writer
+	columns []string
+	keys    [][]byte
+	buf     []byte
+}
+
+// NewJSONLWriter creates a JSON Lines writer.
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	return &JSONLWriter{w: w}
+}
+
+// WriteHeader sets the object keys.
+func (j *JSONLWriter) WriteHeader(columns []string) error {
+	keys := make([][]byte, len(columns))
+	for i, col := range columns {
+		key, err := json.Marshal(col)
+		if err != nil {
+			return err
+		}
+		keys[i] = key
+	}
+	j.columns, j.keys = columns, keys
+	return nil
+}
+
+// WriteRow writes one line.
+func (j *JSONLWriter) WriteRow(values []interface{}) error {
+	if err := checkRow(j.columns, values); err != nil {
+		return err
+	}
+	buf := append(j.buf[:0], '{')
+	for i, v := range values {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, j.keys[i]...)
+		buf = append(buf, ':')
+		buf = append(buf, value...)
+	}
+	buf = append(buf, '}', '\n')
+	j.buf = buf
+	_, err := j.w.Write(buf)
+	return err
+}
+
+// Close does nothing; JSONLWriter does not buffer.
+func (j *JSONLWriter) Close() error {
+	return nil
+}
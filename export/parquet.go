@@ -0,0 +1,320 @@
+package export
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// DefaultRowGroupSize is the number of rows buffered per Parquet row group.
+const DefaultRowGroupSize = 10000
+
+// Parquet format constants, from parquet.thrift.
+const (
+	parquetMagic = "PAR1"
+
+	parquetByteArray = 6 // Type.BYTE_ARRAY
+	parquetOptional  = 1 // FieldRepetitionType.OPTIONAL
+	parquetUTF8      = 0 // ConvertedType.UTF8
+	parquetPlain     = 0 // Encoding.PLAIN
+	parquetRLE       = 3 // Encoding.RLE
+	parquetDataPage  = 0 // PageType.DATA_PAGE
+	parquetNoCodec   = 0 // CompressionCodec.UNCOMPRESSED
+)
+
+// ParquetWriter writes rows as an uncompressed Parquet file. Every column
+// is an optional UTF-8 string holding the value formatted by FormatValue,
+// with nil values stored as nulls. Rows are buffered and written in row
+// groups of RowGroupSize rows.
+type ParquetWriter struct {
+	// RowGroupSize is the number of rows per row group. Defaults to
+	// DefaultRowGroupSize.
+	RowGroupSize int
+
+	w         io.Writer
+	offset    int64
+	columns   []string
+	values    [][]*string
+	rows      int
+	numRows   int64
+	rowGroups []parquetRowGroup
+}
+
+type parquetRowGroup struct {
+	numRows  int64
+	byteSize int64
+	chunks   []parquetChunk
+}
+
+type parquetChunk struct {
+	offset    int64
+	size      int64
+	numValues int64
+}
+
+// NewParquetWriter creates a Parquet writer.
+func NewParquetWriter(w io.Writer) *ParquetWriter {
+	return &ParquetWriter{w: w, RowGroupSize: DefaultRowGroupSize}
+}
+
+// WriteHeader sets the columns and writes the file's leading magic bytes.
+// A Parquet schema needs at least one column.
+func (p *ParquetWriter) WriteHeader(columns []string) error {
+	if len(columns) == 0 {
+		return errors.New("parquet: at least one column is required")
+	}
+	p.columns = columns
+	p.values = make([][]*string, len(columns))
+	return p.write([]byte(parquetMagic))
+}
+
+// WriteRow buffers one row, writing a row group when the buffer is full.
+func (p *ParquetWriter) WriteRow(values []interface{}) error {
+	if err := checkRow(p.columns, values); err != nil {
+		return err
+	}
+	for i, v := range values {
+		var s *string
+		if v != nil {
+			text := FormatValue(v)
+			s = &text
+		}
+		p.values[i] = append(p.values[i], s)
+	}
+	p.rows++
+	if p.rows >= max(p.RowGroupSize, 1) {
+		return p.flushRowGroup()
+	}
+	return nil
+}
+
+// Close writes the buffered rows and the file footer. After a header with
+// no rows it writes a valid file with no row groups. It returns an error
+// if WriteHeader was never called, since a file without columns cannot be
+// written.
+func (p *ParquetWriter) Close() error {
+	if p.columns == nil {
+		return errors.New("parquet: no header written, the columns are unknown")
+	}
+	if p.rows > 0 {
+		if err := p.flushRowGroup(); err != nil {
+			return err
+		}
+	}
+	footer := p.fileMetaData()
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(footer)))
+	footer = append(footer, size[:]...)
+	footer = append(footer, parquetMagic...)
+	return p.write(footer)
+}
+
+func (p *ParquetWriter) write(data []byte) error {
+	n, err := p.w.Write(data)
+	p.offset += int64(n)
+	return err
+}
+
+func (p *ParquetWriter) flushRowGroup() error {
+	group := parquetRowGroup{numRows: int64(p.rows)}
+	for i, values := range p.values {
+		page := encodeDataPage(values)
+		chunk := parquetChunk{offset: p.offset, size: int64(len(page)), numValues: int64(len(values))}
+		if err := p.write(page); err != nil {
+			return err
+		}
+		group.chunks = append(group.chunks, chunk)
+		group.byteSize += chunk.size
+		p.values[i] = values[:0]
+	}
+	p.rowGroups = append(p.rowGroups, group)
+	p.numRows += int64(p.rows)
+	p.rows = 0
+	return nil
+}
+
+// encodeDataPage encodes a column's values as a single v1 data page with
+// RLE definition levels and PLAIN values.
+func encodeDataPage(values []*string) []byte {
+	levels := encodeLevels(values)
+	body := make([]byte, 4, 4+len(levels))
+	binary.LittleEndian.PutUint32(body, uint32(len(levels)))
+	body = append(body, levels...)
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		var size [4]byte
+		binary.LittleEndian.PutUint32(size[:], uint32(len(*v)))
+		body = append(body, size[:]...)
+		body = append(body, *v...)
+	}
+
+	var e thriftEncoder
+	e.i32(1, parquetDataPage)
+	e.i32(2, int32(len(body)))
+	e.i32(3, int32(len(body)))
+	e.beginStruct(5)
+	e.i32(1, int32(len(values)))
+	e.i32(2, parquetPlain)
+	e.i32(3, parquetRLE)
+	e.i32(4, parquetRLE)
+	e.endStruct()
+	e.stop()
+	return append(e.buf, body...)
+}
+
+// encodeLevels encodes definition levels (1 for a value, 0 for null) as
+// RLE runs of the RLE/bit-packing hybrid encoding with bit width 1.
+func encodeLevels(values []*string) []byte {
+	var out []byte
+	for i := 0; i < len(values); {
+		defined := values[i] != nil
+		run := 1
+		for i+run < len(values) && (values[i+run] != nil) == defined {
+			run++
+		}
+		out = binary.AppendUvarint(out, uint64(run)<<1)
+		if defined {
+			out = append(out, 1)
+		} else {
+			out = append(out, 0)
+		}
+		i += run
+	}
+	return out
+}
+
+func (p *ParquetWriter) fileMetaData() []byte {
+	var e thriftEncoder
+	e.i32(1, 1)
+
+	e.listHeader(2, thriftStruct, len(p.columns)+1)
+	e.beginElement()
+	e.binary(4, "schema")
+	e.i32(5, int32(len(p.columns)))
+	e.endStruct()
+	for _, col := range p.columns {
+		e.beginElement()
+		e.i32(1, parquetByteArray)
+		e.i32(3, parquetOptional)
+		e.binary(4, col)
+		e.i32(6, parquetUTF8)
+		e.endStruct()
+	}
+
+	e.i64(3, p.numRows)
+
+	e.listHeader(4, thriftStruct, len(p.rowGroups))
+	for _, group := range p.rowGroups {
+		e.beginElement()
+		e.listHeader(1, thriftStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			e.beginElement()
+			e.i64(2, chunk.offset)
+			e.beginStruct(3)
+			e.i32(1, parquetByteArray)
+			e.listHeader(2, thriftI32, 2)
+			e.varint(int64(parquetPlain))
+			e.varint(int64(parquetRLE))
+			e.listHeader(3, thriftBinary, 1)
+			e.rawBinary(p.columns[i])
+			e.i32(4, parquetNoCodec)
+			e.i64(5, chunk.numValues)
+			e.i64(6, chunk.size)
+			e.i64(7, chunk.size)
+			e.i64(9, chunk.offset)
+			e.endStruct()
+			e.endStruct()
+		}
+		e.i64(2, group.byteSize)
+		e.i64(3, group.numRows)
+		e.endStruct()
+	}
+
+	e.binary(6, "github.com/PramithaMJ/salesforce")
+	e.stop()
+	return e.buf
+}
+
+// Thrift compact protocol type IDs.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftEncoder writes the subset of the Thrift compact protocol needed for
+// Parquet metadata.
+type thriftEncoder struct {
+	buf   []byte
+	last  int
+	stack []int
+}
+
+func (e *thriftEncoder) fieldHeader(id, typ int) {
+	if delta := id - e.last; delta > 0 && delta <= 15 {
+		e.buf = append(e.buf, byte(delta<<4|typ))
+	} else {
+		e.buf = append(e.buf, byte(typ))
+		e.varint(int64(id))
+	}
+	e.last = id
+}
+
+func (e *thriftEncoder) varint(v int64) {
+	e.buf = binary.AppendUvarint(e.buf, uint64((v<<1)^(v>>63)))
+}
+
+func (e *thriftEncoder) i32(id int, v int32) {
+	e.fieldHeader(id, thriftI32)
+	e.varint(int64(v))
+}
+
+func (e *thriftEncoder) i64(id int, v int64) {
+	e.fieldHeader(id, thriftI64)
+	e.varint(v)
+}
+
+func (e *thriftEncoder) binary(id int, s string) {
+	e.fieldHeader(id, thriftBinary)
+	e.rawBinary(s)
+}
+
+func (e *thriftEncoder) rawBinary(s string) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *thriftEncoder) listHeader(id, elemType, size int) {
+	e.fieldHeader(id, thriftList)
+	if size < 15 {
+		e.buf = append(e.buf, byte(size<<4|elemType))
+	} else {
+		e.buf = append(e.buf, byte(0xf0|elemType))
+		e.buf = binary.AppendUvarint(e.buf, uint64(size))
+	}
+}
+
+// beginStruct starts a struct field; beginElement starts a struct list element.
+func (e *thriftEncoder) beginStruct(id int) {
+	e.fieldHeader(id, thriftStruct)
+	e.beginElement()
+}
+
+func (e *thriftEncoder) beginElement() {
+	e.stack = append(e.stack, e.last)
+	e.last = 0
+}
+
+func (e *thriftEncoder) endStruct() {
+	e.stop()
+	e.last = e.stack[len(e.stack)-1]
+	e.stack = e.stack[:len(e.stack)-1]
+}
+
+func (e *thriftEncoder) stop() {
+	e.buf = append(e.buf, 0)
+}
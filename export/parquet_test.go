@@ -0,0 +1,351 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// thriftDecoder reads the Thrift compact protocol into maps keyed by field
+// ID, so tests can check the footer and page headers independently of
+// thriftEncoder.
+type thriftDecoder struct {
+	t   *testing.T
+	buf []byte
+	pos int
+}
+
+func (d *thriftDecoder) byte() byte {
+	if d.pos >= len(d.buf) {
+		d.t.Fatalf("thrift: unexpected end of data at %d", d.pos)
+	}
+	b := d.buf[d.pos]
+	d.pos++
+	return b
+}
+
+func (d *thriftDecoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.buf[d.pos:])
+	if n <= 0 {
+		d.t.Fatalf("thrift: bad varint at %d", d.pos)
+	}
+	d.pos += n
+	return v
+}
+
+func (d *thriftDecoder) zigzag() int64 {
+	v := d.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (d *thriftDecoder) value(typ byte) interface{} {
+	switch typ {
+	case 1:
+		return true
+	case 2:
+		return false
+	case 3:
+		return int64(int8(d.byte()))
+	case 4, 5, 6:
+		return d.zigzag()
+	case 8:
+		n := int(d.uvarint())
+		s := string(d.buf[d.pos : d.pos+n])
+		d.pos += n
+		return s
+	case 9, 10:
+		header := d.byte()
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(d.uvarint())
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i] = d.value(header & 0x0f)
+		}
+		return list
+	case 12:
+		return d.structure()
+	}
+	d.t.Fatalf("thrift: unsupported type %d at %d", typ, d.pos)
+	return nil
+}
+
+func (d *thriftDecoder) structure() map[int]interface{} {
+	fields := make(map[int]interface{})
+	last := 0
+	for {
+		header := d.byte()
+		if header == 0 {
+			return fields
+		}
+		id := last + int(header>>4)
+		if header>>4 == 0 {
+			id = int(d.zigzag())
+		}
+		fields[id] = d.value(header & 0x0f)
+		last = id
+	}
+}
+
+func field(t *testing.T, s map[int]interface{}, id int) interface{} {
+	t.Helper()
+	v, ok := s[id]
+	if !ok {
+		t.Fatalf("field %d missing from %v", id, s)
+	}
+	return v
+}
+
+func structs(t *testing.T, v interface{}) []map[int]interface{} {
+	t.Helper()
+	var out []map[int]interface{}
+	for _, item := range v.([]interface{}) {
+		out = append(out, item.(map[int]interface{}))
+	}
+	return out
+}
+
+// readParquet decodes a file written by ParquetWriter, returning its
+// column names and rows, with nulls as nil.
+func readParquet(t *testing.T, file []byte) ([]string, [][]*string) {
+	t.Helper()
+	if !bytes.HasPrefix(file, []byte(parquetMagic)) || !bytes.HasSuffix(file, []byte(parquetMagic)) {
+		t.Fatal("missing PAR1 magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footerStart := len(file) - 8 - footerLen
+	d := &thriftDecoder{t: t, buf: file[:len(file)-8], pos: footerStart}
+	meta := d.structure()
+	if d.pos != len(file)-8 {
+		t.Fatalf("footer decoded %d bytes, length says %d", d.pos-footerStart, footerLen)
+	}
+	if v := field(t, meta, 1); v != int64(1) {
+		t.Errorf("version = %v, want 1", v)
+	}
+
+	schema := structs(t, field(t, meta, 2))
+	root := schema[0]
+	if n := field(t, root, 5); n != int64(len(schema)-1) {
+		t.Fatalf("root num_children = %v, schema has %d columns", n, len(schema)-1)
+	}
+	var columns []string
+	for _, col := range schema[1:] {
+		if field(t, col, 1) != int64(parquetByteArray) || field(t, col, 3) != int64(parquetOptional) || field(t, col, 6) != int64(parquetUTF8) {
+			t.Errorf("column %v is not an optional UTF-8 byte array", col)
+		}
+		columns = append(columns, field(t, col, 4).(string))
+	}
+
+	numRows := field(t, meta, 3).(int64)
+	var rows [][]*string
+	for _, group := range structs(t, field(t, meta, 4)) {
+		groupRows := int(field(t, group, 3).(int64))
+		chunks := structs(t, field(t, group, 1))
+		if len(chunks) != len(columns) {
+			t.Fatalf("row group has %d chunks for %d columns", len(chunks), len(columns))
+		}
+		groupValues := make([][]*string, len(columns))
+		var byteSize int64
+		for i, chunk := range chunks {
+			cm := field(t, chunk, 3).(map[int]interface{})
+			if path := field(t, cm, 3).([]interface{}); len(path) != 1 || path[0] != columns[i] {
+				t.Errorf("chunk %d path = %v, want [%s]", i, path, columns[i])
+			}
+			if v := field(t, cm, 5); v != int64(groupRows) {
+				t.Errorf("chunk %d num_values = %v, want %d", i, v, groupRows)
+			}
+			offset := field(t, cm, 9).(int64)
+			if field(t, chunk, 2) != offset {
+				t.Errorf("chunk %d file_offset differs from data_page_offset", i)
+			}
+			size := field(t, cm, 7).(int64)
+			byteSize += size
+			groupValues[i] = readPage(t, file, offset, size, groupRows)
+		}
+		if v := field(t, group, 2); v != byteSize {
+			t.Errorf("row group total_byte_size = %v, want %d", v, byteSize)
+		}
+		for r := 0; r < groupRows; r++ {
+			row := make([]*string, len(columns))
+			for c := range columns {
+				row[c] = groupValues[c][r]
+			}
+			rows = append(rows, row)
+		}
+	}
+	if int64(len(rows)) != numRows {
+		t.Errorf("num_rows = %d, row groups hold %d", numRows, len(rows))
+	}
+	return columns, rows
+}
+
+// readPage decodes a v1 data page with RLE definition levels and PLAIN
+// byte array values.
+func readPage(t *testing.T, file []byte, offset, size int64, numValues int) []*string {
+	t.Helper()
+	d := &thriftDecoder{t: t, buf: file[:offset+size], pos: int(offset)}
+	header := d.structure()
+	if field(t, header, 1) != int64(parquetDataPage) {
+		t.Fatalf("page type = %v, want data page", header[1])
+	}
+	bodyLen := int(field(t, header, 3).(int64))
+	if field(t, header, 2) != int64(bodyLen) {
+		t.Errorf("uncompressed size differs from compressed size for an uncompressed page")
+	}
+	if int64(d.pos)+int64(bodyLen) != offset+size {
+		t.Fatalf("page header and body are %d bytes, chunk is %d", int64(d.pos)+int64(bodyLen)-offset, size)
+	}
+	dph := field(t, header, 5).(map[int]interface{})
+	if field(t, dph, 1) != int64(numValues) || field(t, dph, 2) != int64(parquetPlain) || field(t, dph, 3) != int64(parquetRLE) {
+		t.Errorf("data page header = %v", dph)
+	}
+
+	body := file[d.pos : d.pos+bodyLen]
+	levelsLen := int(binary.LittleEndian.Uint32(body))
+	levels := body[4 : 4+levelsLen]
+	var defined []bool
+	for len(levels) > 0 {
+		h, n := binary.Uvarint(levels)
+		if h&1 != 0 {
+			t.Fatal("unexpected bit-packed run")
+		}
+		for i := uint64(0); i < h>>1; i++ {
+			defined = append(defined, levels[n] == 1)
+		}
+		levels = levels[n+1:]
+	}
+	if len(defined) != numValues {
+		t.Fatalf("%d definition levels for %d values", len(defined), numValues)
+	}
+	data := body[4+levelsLen:]
+	values := make([]*string, numValues)
+	for i, ok := range defined {
+		if !ok {
+			continue
+		}
+		n := int(binary.LittleEndian.Uint32(data))
+		s := string(data[4 : 4+n])
+		values[i] = &s
+		data = data[4+n:]
+	}
+	if len(data) != 0 {
+		t.Errorf("%d bytes left after values", len(data))
+	}
+	return values
+}
+
+func str(s string) *string { return &s }
+
+func TestParquetRoundTrip(t *testing.T) {
+	at := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	input := [][]interface{}{
+		{"001", "Acme", 1.5, true},
+		{"002", nil, nil, false},
+		{"003", "", 2, nil},
+		{"004", "Ünïcødé", at, nil},
+		{"005", nil, nil, nil},
+	}
+	var buf bytes.Buffer
+	w := NewParquetWriter(&buf)
+	w.RowGroupSize = 2
+	if err := w.WriteHeader([]string{"Id", "Name", "Value", "Flag"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range input {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	columns, rows := readParquet(t, buf.Bytes())
+	if want := []string{"Id", "Name", "Value", "Flag"}; !reflect.DeepEqual(columns, want) {
+		t.Errorf("columns = %v, want %v", columns, want)
+	}
+	want := [][]*string{
+		{str("001"), str("Acme"), str("1.5"), str("true")},
+		{str("002"), nil, nil, str("false")},
+		{str("003"), str(""), str("2"), nil},
+		{str("004"), str("Ünïcødé"), str(at.Format(time.RFC3339Nano)), nil},
+		{str("005"), nil, nil, nil},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %s, want %s", formatRows(rows), formatRows(want))
+	}
+}
+
+func TestParquetManyColumns(t *testing.T) {
+	columns := make([]string, 20)
+	values := make([]interface{}, 20)
+	for i := range columns {
+		columns[i] = fmt.Sprintf("Field%d__c", i)
+		values[i] = i
+	}
+	var buf bytes.Buffer
+	w := NewParquetWriter(&buf)
+	if err := w.WriteHeader(columns); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow(values); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	gotColumns, rows := readParquet(t, buf.Bytes())
+	if !reflect.DeepEqual(gotColumns, columns) {
+		t.Errorf("columns = %v, want %v", gotColumns, columns)
+	}
+	if len(rows) != 1 || *rows[0][19] != "19" {
+		t.Errorf("rows = %s", formatRows(rows))
+	}
+}
+
+func TestParquetNoRows(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewParquetWriter(&buf)
+	if err := w.WriteHeader([]string{"Id"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	columns, rows := readParquet(t, buf.Bytes())
+	if len(columns) != 1 || len(rows) != 0 {
+		t.Errorf("columns = %v, rows = %d", columns, len(rows))
+	}
+}
+
+func TestParquetNoColumns(t *testing.T) {
+	if err := NewParquetWriter(&bytes.Buffer{}).WriteHeader([]string{}); err == nil {
+		t.Error("WriteHeader with no columns succeeded")
+	}
+	if err := NewParquetWriter(&bytes.Buffer{}).Close(); err == nil {
+		t.Error("Close without a header succeeded")
+	}
+}
+
+func formatRows(rows [][]*string) string {
+	var b bytes.Buffer
+	for _, row := range rows {
+		b.WriteString("[")
+		for i, v := range row {
+			if i > 0 {
+				b.WriteString(" ")
+			}
+			if v == nil {
+				b.WriteString("<nil>")
+			} else {
+				fmt.Fprintf(&b, "%q", *v)
+			}
+		}
+		b.WriteString("]")
+	}
+	return b.String()
+}
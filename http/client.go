@@ -99,6 +99,67 @@ func (c *Client) Delete(ctx context.Context, path string) ([]byte, error) {
 	return c.doRequest(ctx, http.MethodDelete, path, nil, "")
 }
 
+// Request describes a raw request whose body is streamed to Salesforce.
+type Request struct {
+	Method      string
+	Path        string
+	Body        io.Reader
+	ContentType string
+	Accept      string
+	Headers     map[string]string
+	// NoTimeout exempts the request from the http.Client's Timeout, for
+	// bodies streamed over longer than it. ctx still bounds the request.
+	NoTimeout bool
+}
+
+// Response is a raw response whose body is streamed from Salesforce.
+// The caller must close Body.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       io.ReadCloser
+}
+
+// Do performs a raw request and returns the unread response body.
+// Because the request body may be a one-shot stream, Do does not retry.
+func (c *Client) Do(ctx context.Context, r Request) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, r.Method, c.resolveURL(r.Path), r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	if r.ContentType != "" {
+		req.Header.Set("Content-Type", r.ContentType)
+	}
+	accept := r.Accept
+	if accept == "" {
+		accept = "application/json"
+	}
+	req.Header.Set("Accept", accept)
+	for k, v := range r.Headers {
+		req.Header.Set(k, v)
+	}
+	httpClient := c.httpClient
+	if r.NoTimeout && httpClient.Timeout > 0 {
+		untimed := *httpClient
+		untimed.Timeout = 0
+		httpClient = &untimed
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		return nil, types.ParseAPIError(resp.StatusCode, respBody)
+	}
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: resp.Body}, nil
+}
+
 func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}, contentType string) ([]byte, error) {
 	var lastErr error
 	for attempt := 0; attempt <= c.maxRetries; attempt++ {
@@ -126,12 +187,7 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 }
 
 func (c *Client) executeRequest(ctx context.Context, method, path string, body interface{}, contentType string) ([]byte, error) {
-	url := c.baseURL + path
-	if !strings.HasPrefix(path, "http") && strings.HasPrefix(path, "/services/") {
-		url = c.baseURL + path
-	} else if strings.HasPrefix(path, "http") {
-		url = path
-	}
+	url := c.resolveURL(path)
 	var reqBody io.Reader
 	if body != nil {
 		switch v := body.(type) {
@@ -173,6 +229,13 @@ func (c *Client) executeRequest(ctx context.Context, method, path string, body i
 	return respBody, nil
 }
 
+func (c *Client) resolveURL(path string) string {
+	if strings.HasPrefix(path, "http") {
+		return path
+	}
+	return c.baseURL + path
+}
+
 // APIVersion returns the API version.
 func (c *Client) APIVersion() string { return c.apiVersion }
 
@@ -0,0 +1,270 @@
+// Package process provides Process Approvals and Process Rules operations.
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/PramithaMJ/salesforce/v2/types"
+)
+
+// ActionType is the approval action to perform.
+type ActionType string
+
+const (
+	ActionSubmit  ActionType = "Submit"
+	ActionApprove ActionType = "Approve"
+	ActionReject  ActionType = "Reject"
+)
+
+// Approval describes an approval process.
+type Approval struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Object      string `json:"object"`
+	SortOrder   int    `json:"sortOrder"`
+}
+
+// ApprovalList contains approval processes keyed by object type.
+type ApprovalList struct {
+	Approvals map[string][]Approval `json:"approvals"`
+}
+
+// ApprovalRequest is a single submit, approve or reject request. For
+// Submit, ContextID is the record ID; for Approve and Reject it is the
+// ProcessInstanceWorkitem ID.
+type ApprovalRequest struct {
+	ActionType                ActionType `json:"actionType"`
+	ContextID                 string     `json:"contextId"`
+	Comments                  string     `json:"comments,omitempty"`
+	NextApproverIDs           []string   `json:"nextApproverIds,omitempty"`
+	ContextActorID            string     `json:"contextActorId,omitempty"`
+	ProcessDefinitionNameOrID string     `json:"processDefinitionNameOrId,omitempty"`
+	SkipEntryCriteria         bool       `json:"skipEntryCriteria,omitempty"`
+}
+
+// Error represents a process request error.
+type Error struct {
+	StatusCode string   `json:"statusCode"`
+	Message    string   `json:"message"`
+	Fields     []string `json:"fields,omitempty"`
+}
+
+// ApprovalResult is the outcome of a single approval request.
+type ApprovalResult struct {
+	ActorIDs       []string `json:"actorIds"`
+	EntityID       string   `json:"entityId"`
+	Errors         []Error  `json:"errors,omitempty"`
+	InstanceID     string   `json:"instanceId"`
+	InstanceStatus string   `json:"instanceStatus"`
+	NewWorkitemIDs []string `json:"newWorkitemIds"`
+	Success        bool     `json:"success"`
+}
+
+// Err returns the request errors as an error, or nil on success.
+func (r *ApprovalResult) Err() error {
+	return toError(r.Success, r.Errors)
+}
+
+// RuleAction is an action triggered by a workflow rule.
+type RuleAction struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Rule describes a workflow rule.
+type Rule struct {
+	ID              string       `json:"id"`
+	Name            string       `json:"name"`
+	Description     string       `json:"description,omitempty"`
+	NamespacePrefix string       `json:"namespacePrefix,omitempty"`
+	Object          string       `json:"object"`
+	Actions         []RuleAction `json:"actions"`
+}
+
+// RuleList contains workflow rules keyed by object type.
+type RuleList struct {
+	Rules map[string][]Rule `json:"rules"`
+}
+
+// TriggerResult is the outcome of triggering workflow rules.
+type TriggerResult struct {
+	Errors  []Error `json:"errors,omitempty"`
+	Success bool    `json:"success"`
+}
+
+// Err returns the trigger errors as an error, or nil on success.
+func (r *TriggerResult) Err() error {
+	return toError(r.Success, r.Errors)
+}
+
+// HTTPClient interface for dependency injection.
+type HTTPClient interface {
+	Get(ctx context.Context, path string) ([]byte, error)
+	Post(ctx context.Context, path string, body interface{}) ([]byte, error)
+}
+
+// Service provides Process API operations.
+type Service struct {
+	client     HTTPClient
+	apiVersion string
+}
+
+// NewService creates a new Process service.
+func NewService(client HTTPClient, apiVersion string) *Service {
+	return &Service{client: client, apiVersion: apiVersion}
+}
+
+// ListApprovals lists the approval processes, keyed by object type.
+func (s *Service) ListApprovals(ctx context.Context) (map[string][]Approval, error) {
+	path := fmt.Sprintf("/services/data/v%s/process/approvals", s.apiVersion)
+	respBody, err := s.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var result ApprovalList
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return result.Approvals, nil
+}
+
+// ProcessApprovals sends submit, approve and reject requests in one call
+// and returns one result per request.
+func (s *Service) ProcessApprovals(ctx context.Context, requests []ApprovalRequest) ([]ApprovalResult, error) {
+	if len(requests) == 0 {
+		return nil, errors.New("at least one approval request is required")
+	}
+	path := fmt.Sprintf("/services/data/v%s/process/approvals", s.apiVersion)
+	respBody, err := s.client.Post(ctx, path, map[string]interface{}{"requests": requests})
+	if err != nil {
+		return nil, err
+	}
+	var results []ApprovalResult
+	if err := json.Unmarshal(respBody, &results); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return results, nil
+}
+
+// Submit submits a record for approval. processName may be empty to use
+// the first applicable process.
+func (s *Service) Submit(ctx context.Context, recordID, comments string, nextApproverIDs []string, processName string) (*ApprovalResult, error) {
+	return s.single(ctx, ApprovalRequest{
+		ActionType:                ActionSubmit,
+		ContextID:                 recordID,
+		Comments:                  comments,
+		NextApproverIDs:           nextApproverIDs,
+		ProcessDefinitionNameOrID: processName,
+	})
+}
+
+// Approve approves a pending work item.
+func (s *Service) Approve(ctx context.Context, workitemID, comments string, nextApproverIDs []string) (*ApprovalResult, error) {
+	return s.single(ctx, ApprovalRequest{
+		ActionType:      ActionApprove,
+		ContextID:       workitemID,
+		Comments:        comments,
+		NextApproverIDs: nextApproverIDs,
+	})
+}
+
+// Reject rejects a pending work item.
+func (s *Service) Reject(ctx context.Context, workitemID, comments string) (*ApprovalResult, error) {
+	return s.single(ctx, ApprovalRequest{
+		ActionType: ActionReject,
+		ContextID:  workitemID,
+		Comments:   comments,
+	})
+}
+
+// ListRules lists all active workflow rules, keyed by object type.
+func (s *Service) ListRules(ctx context.Context) (map[string][]Rule, error) {
+	path := fmt.Sprintf("/services/data/v%s/process/rules", s.apiVersion)
+	respBody, err := s.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var result RuleList
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return result.Rules, nil
+}
+
+// ListRulesForObject lists the active workflow rules of an object type.
+func (s *Service) ListRulesForObject(ctx context.Context, objectType string) ([]Rule, error) {
+	path := fmt.Sprintf("/services/data/v%s/process/rules/%s", s.apiVersion, objectType)
+	respBody, err := s.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var result RuleList
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return result.Rules[objectType], nil
+}
+
+// GetRule retrieves a single workflow rule.
+func (s *Service) GetRule(ctx context.Context, objectType, ruleID string) (*Rule, error) {
+	path := fmt.Sprintf("/services/data/v%s/process/rules/%s/%s", s.apiVersion, objectType, ruleID)
+	respBody, err := s.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var rule Rule
+	if err := json.Unmarshal(respBody, &rule); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &rule, nil
+}
+
+// TriggerRules evaluates the workflow rules for the given records.
+func (s *Service) TriggerRules(ctx context.Context, recordIDs []string) (*TriggerResult, error) {
+	if len(recordIDs) == 0 {
+		return nil, errors.New("at least one record ID is required")
+	}
+	path := fmt.Sprintf("/services/data/v%s/process/rules", s.apiVersion)
+	respBody, err := s.client.Post(ctx, path, map[string]interface{}{"contextIds": recordIDs})
+	if err != nil {
+		return nil, err
+	}
+	var results []TriggerResult
+	if err := json.Unmarshal(respBody, &results); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, errors.New("no trigger result returned")
+	}
+	return &results[0], results[0].Err()
+}
+
+func (s *Service) single(ctx context.Context, req ApprovalRequest) (*ApprovalResult, error) {
+	results, err := s.ProcessApprovals(ctx, []ApprovalRequest{req})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, errors.New("no approval result returned")
+	}
+	return &results[0], results[0].Err()
+}
+
+func toError(success bool, errs []Error) error {
+	if success {
+		return nil
+	}
+	if len(errs) == 0 {
+		return &types.APIError{Message: "process request failed", ErrorCode: "UNKNOWN_ERROR"}
+	}
+	apiErrs := make(types.APIErrors, len(errs))
+	for i, e := range errs {
+		apiErrs[i] = types.APIError{Message: e.Message, ErrorCode: types.ErrorCode(e.StatusCode), Fields: e.Fields}
+	}
+	return apiErrs
+}
@@ -0,0 +1,92 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxRollupFields is the most fields GROUP BY ROLLUP or CUBE accepts.
+const MaxRollupFields = 3
+
+// Grouping returns GROUPING(field). In ROLLUP and CUBE queries it selects 1
+// for subtotal rows that aggregate over field, and 0 otherwise.
+func Grouping(field string) string { return "GROUPING(" + field + ")" }
+
+// ExprName returns the name Salesforce gives the nth unaliased aggregate or
+// date function in a SELECT list, counting from 0: expr0, expr1 and so on.
+func ExprName(n int) string { return fmt.Sprintf("expr%d", n) }
+
+// GroupByRollup adds GROUP BY ROLLUP(fields...), which adds subtotal rows
+// for each level of the listed fields and a grand total row.
+func (b *Builder) GroupByRollup(fields ...string) *Builder {
+	return b.GroupBy("ROLLUP(" + strings.Join(fields, ", ") + ")")
+}
+
+// GroupByCube adds GROUP BY CUBE(fields...), which adds subtotal rows for
+// every combination of the listed fields and a grand total row.
+func (b *Builder) GroupByCube(fields ...string) *Builder {
+	return b.GroupBy("CUBE(" + strings.Join(fields, ", ") + ")")
+}
+
+func (b *Builder) validateGroupBy() error {
+	for _, g := range b.groupBy {
+		upper := strings.ToUpper(strings.TrimSpace(g))
+		if !strings.HasPrefix(upper, "ROLLUP(") && !strings.HasPrefix(upper, "CUBE(") {
+			continue
+		}
+		if len(b.groupBy) > 1 {
+			return errors.New("GROUP BY ROLLUP or CUBE cannot be combined with other grouping fields")
+		}
+		args := strings.TrimSuffix(g[strings.Index(g, "(")+1:], ")")
+		if n := len(strings.Split(args, ",")); n > MaxRollupFields {
+			return fmt.Errorf("GROUP BY ROLLUP or CUBE accepts at most %d fields, got %d", MaxRollupFields, n)
+		}
+	}
+	return nil
+}
+
+// AggregateResult is a record returned by an aggregate query. Values are
+// found under their alias, under ExprName(n) for unaliased aggregates, or under
+// the field name for grouped fields. Numbers are read with FloatField and
+// IntField.
+type AggregateResult struct {
+	*SObject
+}
+
+// AggregateResults returns the result's records as aggregate results.
+func (r *Result) AggregateResults() []*AggregateResult {
+	results := make([]*AggregateResult, len(r.Records))
+	for i, record := range r.Records {
+		results[i] = &AggregateResult{SObject: record}
+	}
+	return results
+}
+
+// Grouping returns true if the GROUPING() value under key is 1, meaning
+// the row is a ROLLUP or CUBE subtotal over that field. The field's own
+// value is null in such rows.
+func (a *AggregateResult) Grouping(key string) bool {
+	return a.IntField(key) == 1
+}
+
+// Count runs a query as SELECT COUNT() and returns the number of matching
+// records. Queries selecting fields keep their FROM, WHERE and LIMIT
+// clauses; ORDER BY is dropped.
+func (s *Service) Count(ctx context.Context, soql string) (int, error) {
+	q, err := Parse(soql)
+	if err != nil {
+		return 0, err
+	}
+	if len(q.GroupBy) > 0 {
+		return 0, errors.New("Count does not support GROUP BY queries")
+	}
+	q.Select = []SelectItem{{Expr: &Func{Name: "COUNT"}}}
+	q.OrderBy = nil
+	result, err := s.Execute(ctx, q.String())
+	if err != nil {
+		return 0, err
+	}
+	return result.TotalSize, nil
+}
@@ -0,0 +1,325 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pos is a position in SOQL source. Line and Column start at 1.
+type Pos struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+// String returns the position as line:column.
+func (p Pos) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Node is an element of a parsed query.
+type Node interface {
+	Position() Pos
+	String() string
+}
+
+// Expr is a boolean expression in WHERE or HAVING.
+type Expr interface {
+	Node
+	expr()
+}
+
+// Field is a field reference, possibly a relationship path such as Account.Owner.Name.
+type Field struct {
+	Pos  Pos
+	Name string
+}
+
+func (f *Field) Position() Pos  { return f.Pos }
+func (f *Field) String() string { return f.Name }
+
+// Func is a function call such as COUNT(Id) or CALENDAR_YEAR(CloseDate).
+type Func struct {
+	Pos  Pos
+	Name string
+	Args []Node
+}
+
+func (f *Func) Position() Pos { return f.Pos }
+func (f *Func) String() string {
+	args := make([]string, len(f.Args))
+	for i, a := range f.Args {
+		args[i] = a.String()
+	}
+	return f.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// LiteralKind is the kind of a literal value.
+type LiteralKind int
+
+const (
+	LiteralString LiteralKind = iota
+	LiteralNumber
+	LiteralBool
+	LiteralNull
+	LiteralDate
+	LiteralDateTime
+	// LiteralConstant is an unquoted constant: a date literal such as
+	// LAST_N_DAYS:30 or a currency literal such as USD5000.
+	LiteralConstant
+	// LiteralBind is an Apex bind variable such as :accountIds.
+	LiteralBind
+)
+
+// Literal is a literal value. Text is the value as written, including quotes.
+type Literal struct {
+	Pos  Pos
+	Kind LiteralKind
+	Text string
+}
+
+func (l *Literal) Position() Pos  { return l.Pos }
+func (l *Literal) String() string { return l.Text }
+
+// Value returns a string literal without quotes and escapes, or Text otherwise.
+func (l *Literal) Value() string {
+	if l.Kind != LiteralString || len(l.Text) < 2 {
+		return l.Text
+	}
+	var sb strings.Builder
+	body := l.Text[1 : len(l.Text)-1]
+	for i := 0; i < len(body); i++ {
+		if body[i] == '\\' && i+1 < len(body) {
+			i++
+			switch body[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			default:
+				sb.WriteByte(body[i])
+			}
+			continue
+		}
+		sb.WriteByte(body[i])
+	}
+	return sb.String()
+}
+
+// List is a parenthesized list of literals on the right of IN, INCLUDES or EXCLUDES.
+type List struct {
+	Pos    Pos
+	Values []*Literal
+}
+
+func (l *List) Position() Pos { return l.Pos }
+func (l *List) String() string {
+	values := make([]string, len(l.Values))
+	for i, v := range l.Values {
+		values[i] = v.String()
+	}
+	return "(" + strings.Join(values, ", ") + ")"
+}
+
+// TypeOfExpr is a TYPEOF expression in a select list.
+type TypeOfExpr struct {
+	Pos   Pos
+	Field *Field
+	Whens []TypeOfWhen
+	Else  []*Field
+}
+
+// TypeOfWhen is a WHEN branch of a TYPEOF expression.
+type TypeOfWhen struct {
+	Pos        Pos
+	ObjectType string
+	Fields     []*Field
+}
+
+func (t *TypeOfExpr) Position() Pos { return t.Pos }
+func (t *TypeOfExpr) String() string {
+	typeOf := NewTypeOf(t.Field.Name)
+	for _, w := range t.Whens {
+		typeOf.When(w.ObjectType, fieldNames(w.Fields)...)
+	}
+	typeOf.Else(fieldNames(t.Else)...)
+	return typeOf.String()
+}
+
+// SelectItem is an element of a select list: a field, function, TYPEOF
+// expression or child relationship subquery.
+type SelectItem struct {
+	Expr  Node
+	Alias string
+}
+
+// String renders the select item.
+func (s SelectItem) String() string {
+	if q, ok := s.Expr.(*Query); ok {
+		return "(" + q.String() + ")"
+	}
+	if s.Alias != "" {
+		return s.Expr.String() + " " + s.Alias
+	}
+	return s.Expr.String()
+}
+
+// From is the FROM clause of a query.
+type From struct {
+	Pos   Pos
+	Name  string
+	Alias string
+}
+
+// OrderItem is an element of ORDER BY.
+type OrderItem struct {
+	Expr      Node
+	Direction string // ASC, DESC or empty
+	Nulls     string // FIRST, LAST or empty
+}
+
+// String renders the order item.
+func (o OrderItem) String() string {
+	s := o.Expr.String()
+	if o.Direction != "" {
+		s += " " + o.Direction
+	}
+	if o.Nulls != "" {
+		s += " NULLS " + o.Nulls
+	}
+	return s
+}
+
+// LogicalExpr combines operands with AND or OR.
+type LogicalExpr struct {
+	Pos      Pos
+	Op       string
+	Operands []Expr
+}
+
+func (e *LogicalExpr) Position() Pos  { return e.Pos }
+func (e *LogicalExpr) String() string { return toCondition(e).SOQL() }
+func (e *LogicalExpr) expr()          {}
+
+// NotExpr negates an expression.
+type NotExpr struct {
+	Pos Pos
+	X   Expr
+}
+
+func (e *NotExpr) Position() Pos  { return e.Pos }
+func (e *NotExpr) String() string { return toCondition(e).SOQL() }
+func (e *NotExpr) expr()          {}
+
+// ComparisonExpr compares a field or function with a value. Right is a
+// *Literal, a *List, or a *Query for semi-joins and anti-joins.
+type ComparisonExpr struct {
+	Pos   Pos
+	Left  Node
+	Op    string
+	Right Node
+}
+
+func (e *ComparisonExpr) Position() Pos  { return e.Pos }
+func (e *ComparisonExpr) String() string { return toCondition(e).SOQL() }
+func (e *ComparisonExpr) expr()          {}
+
+// Query is a parsed SOQL query.
+type Query struct {
+	Pos     Pos
+	Select  []SelectItem
+	From    From
+	Scope   string
+	Where   Expr
+	With    string
+	GroupBy []Node
+	Having  Expr
+	OrderBy []OrderItem
+	Limit   int
+	Offset  int
+	For     []string
+}
+
+// Position returns the position of the SELECT keyword.
+func (q *Query) Position() Pos { return q.Pos }
+
+// String renders the query as SOQL.
+func (q *Query) String() string { return q.Builder().Build() }
+
+// Builder converts the query into a Builder so it can be modified.
+// Child subqueries are rendered after the other select items.
+func (q *Query) Builder() *Builder {
+	objectType := q.From.Name
+	if q.From.Alias != "" {
+		objectType += " " + q.From.Alias
+	}
+	b := NewBuilder(objectType)
+	for _, item := range q.Select {
+		if sub, ok := item.Expr.(*Query); ok {
+			b.SelectSubquery(sub.Builder())
+			continue
+		}
+		b.Select(item.String())
+	}
+	if q.Scope != "" {
+		b.UsingScope(Scope(q.Scope))
+	}
+	if q.Where != nil {
+		b.WhereExpr(toCondition(q.Where))
+	}
+	if q.With != "" {
+		b.addSecurity(Security(q.With))
+	}
+	for _, g := range q.GroupBy {
+		b.GroupBy(g.String())
+	}
+	if q.Having != nil {
+		b.HavingExpr(toCondition(q.Having))
+	}
+	for _, o := range q.OrderBy {
+		b.orderBy = append(b.orderBy, o.String())
+	}
+	b.limit = q.Limit
+	b.offset = q.Offset
+	for _, f := range q.For {
+		switch f {
+		case "VIEW":
+			b.forView = true
+		case "REFERENCE":
+			b.forRef = true
+		case "UPDATE":
+			b.forUpdate = true
+		}
+	}
+	return b
+}
+
+// toCondition converts a parsed expression into a builder Condition.
+func toCondition(e Expr) Condition {
+	switch v := e.(type) {
+	case *LogicalExpr:
+		conditions := make([]Condition, len(v.Operands))
+		for i, o := range v.Operands {
+			conditions[i] = toCondition(o)
+		}
+		return junction{operator: v.Op, conditions: conditions}
+	case *NotExpr:
+		return negation{condition: toCondition(v.X)}
+	case *ComparisonExpr:
+		if sub, ok := v.Right.(*Query); ok {
+			return semiJoin{field: v.Left.String(), operator: v.Op, sub: sub.Builder()}
+		}
+		return comparison{field: v.Left.String(), operator: v.Op, value: v.Right.String()}
+	}
+	return nil
+}
+
+func fieldNames(fields []*Field) []string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+	}
+	return names
+}
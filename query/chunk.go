@@ -0,0 +1,323 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PramithaMJ/salesforce/v2/types"
+)
+
+// Fields a query can be chunked on.
+const (
+	ChunkByID          = "Id"
+	ChunkByCreatedDate = "CreatedDate"
+)
+
+// Defaults for ChunkOptions.
+const (
+	DefaultChunkParallelism = 4
+	DefaultChunksPerWorker  = 4
+)
+
+// ChunkOptions configures chunked query execution.
+type ChunkOptions struct {
+	// Field is ChunkByID (the default) or ChunkByCreatedDate.
+	Field string
+	// Parallelism is the number of chunks queried at once. Defaults to
+	// DefaultChunkParallelism.
+	Parallelism int
+	// Chunks is the number of ranges the query is split into. Defaults to
+	// Parallelism * DefaultChunksPerWorker.
+	Chunks int
+	// BatchSize is passed to each chunk's iterator.
+	BatchSize int
+	// IncludeDeleted runs the chunks through queryAll.
+	IncludeDeleted bool
+}
+
+func (o *ChunkOptions) withDefaults() ChunkOptions {
+	opts := ChunkOptions{}
+	if o != nil {
+		opts = *o
+	}
+	if opts.Field == "" {
+		opts.Field = ChunkByID
+	}
+	if opts.Parallelism <= 0 {
+		opts.Parallelism = DefaultChunkParallelism
+	}
+	if opts.Chunks <= 0 {
+		opts.Chunks = opts.Parallelism * DefaultChunksPerWorker
+	}
+	return opts
+}
+
+// ChunkQueries splits a query into queries over disjoint ranges of the
+// chunk field. The range is discovered with ORDER BY ... LIMIT 1 probes
+// for the lowest and highest values and divided evenly. It returns no
+// queries when the query matches no records.
+func (s *Service) ChunkQueries(ctx context.Context, soql string, opts *ChunkOptions) ([]string, error) {
+	o := opts.withDefaults()
+	q, err := Parse(soql)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkChunkable(q, o.Field); err != nil {
+		return nil, err
+	}
+	low, err := s.probe(ctx, q, o, "ASC")
+	if err != nil || low == nil {
+		return nil, err
+	}
+	high, err := s.probe(ctx, q, o, "DESC")
+	if err != nil || high == nil {
+		return nil, err
+	}
+	var bounds []interface{}
+	switch o.Field {
+	case ChunkByID:
+		bounds, err = idBounds(low.StringField("Id"), high.StringField("Id"), o.Chunks)
+	case ChunkByCreatedDate:
+		bounds, err = dateBounds(low.StringField(ChunkByCreatedDate), high.StringField(ChunkByCreatedDate), o.Chunks)
+	}
+	if err != nil {
+		return nil, err
+	}
+	queries := make([]string, 0, len(bounds)-1)
+	for i := 0; i < len(bounds)-1; i++ {
+		b := q.Builder().WhereExpr(GreaterOrEqual(o.Field, bounds[i]))
+		if i == len(bounds)-2 {
+			b.WhereExpr(LessOrEqual(o.Field, bounds[i+1]))
+		} else {
+			b.WhereExpr(LessThan(o.Field, bounds[i+1]))
+		}
+		queries = append(queries, b.Build())
+	}
+	return queries, nil
+}
+
+// ExecuteChunked splits a query with ChunkQueries, runs the chunks
+// concurrently and calls fn for each record. fn is called from one
+// goroutine at a time; records arrive in no particular order. The first
+// error from a chunk or from fn stops all chunks. If ctx is cancelled
+// first, its error is returned.
+func (s *Service) ExecuteChunked(ctx context.Context, soql string, opts *ChunkOptions, fn func(*SObject) error) error {
+	o := opts.withDefaults()
+	queries, err := s.ChunkQueries(ctx, soql, &o)
+	if err != nil {
+		return err
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	records := make(chan *SObject, o.Parallelism*MinBatchSize)
+	work := make(chan string)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	for i := 0; i < min(o.Parallelism, len(queries)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for q := range work {
+				if err := s.runChunk(runCtx, q, &o, records); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+	go func() {
+		defer close(work)
+		for _, q := range queries {
+			select {
+			case work <- q:
+			case <-runCtx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(records)
+	}()
+
+	for record := range records {
+		if runCtx.Err() != nil {
+			continue
+		}
+		if err := fn(record); err != nil {
+			fail(err)
+		}
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+	// Chunks stopped by the caller's cancellation report no error of
+	// their own.
+	return ctx.Err()
+}
+
+// ExecuteChunkedAll runs a chunked query and returns all records.
+func (s *Service) ExecuteChunkedAll(ctx context.Context, soql string, opts *ChunkOptions) ([]*SObject, error) {
+	var all []*SObject
+	err := s.ExecuteChunked(ctx, soql, opts, func(r *SObject) error {
+		all = append(all, r)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+func (s *Service) runChunk(ctx context.Context, soql string, o *ChunkOptions, records chan<- *SObject) error {
+	it := s.Iterate(ctx, soql, &IteratorOptions{BatchSize: o.BatchSize, IncludeDeleted: o.IncludeDeleted})
+	defer it.Close()
+	for it.Next() {
+		select {
+		case records <- it.Record():
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	if err := it.Err(); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// probe returns the record with the lowest or highest chunk field value.
+func (s *Service) probe(ctx context.Context, q *Query, o ChunkOptions, direction string) (*SObject, error) {
+	b := q.Builder()
+	b.fields = []string{o.Field}
+	b.subqueries = nil
+	b.orderBy = []string{o.Field + " " + direction}
+	b.limit = 1
+	var (
+		result *Result
+		err    error
+	)
+	if o.IncludeDeleted {
+		result, err = s.ExecuteAll(ctx, b.Build())
+	} else {
+		result, err = s.Execute(ctx, b.Build())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Records) == 0 {
+		return nil, nil
+	}
+	return result.Records[0], nil
+}
+
+func checkChunkable(q *Query, field string) error {
+	if field != ChunkByID && field != ChunkByCreatedDate {
+		return fmt.Errorf("cannot chunk on %s, use %s or %s", field, ChunkByID, ChunkByCreatedDate)
+	}
+	b := q.Builder()
+	switch {
+	case q.Limit > 0 || q.Offset > 0:
+		return errors.New("chunked queries cannot use LIMIT or OFFSET")
+	case len(q.OrderBy) > 0:
+		return errors.New("chunked queries cannot use ORDER BY, records arrive in chunk order")
+	case len(q.GroupBy) > 0 || b.hasAggregate():
+		return errors.New("chunked queries cannot be aggregate queries")
+	case len(q.For) > 0:
+		return errors.New("chunked queries cannot use FOR clauses")
+	}
+	return nil
+}
+
+const base62Digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// idBounds divides the range between two record IDs evenly by treating
+// their 15-character form as base-62 numbers.
+func idBounds(low, high string, chunks int) ([]interface{}, error) {
+	lowID, err := types.ParseID(low)
+	if err != nil {
+		return nil, err
+	}
+	highID, err := types.ParseID(high)
+	if err != nil {
+		return nil, err
+	}
+	lo, hi := decodeBase62(string(lowID.To15())), decodeBase62(string(highID.To15()))
+	span := new(big.Int).Sub(hi, lo)
+	n := big.NewInt(int64(chunks))
+	if span.Cmp(n) < 0 {
+		n = new(big.Int).Add(span, big.NewInt(1))
+	}
+	bounds := []interface{}{lowID.To15()}
+	last := lowID.To15()
+	for i := int64(1); i < n.Int64(); i++ {
+		step := new(big.Int).Mul(span, big.NewInt(i))
+		step.Div(step, n)
+		id := types.ID(encodeBase62(new(big.Int).Add(lo, step), 15))
+		if id != last {
+			bounds = append(bounds, id)
+			last = id
+		}
+	}
+	if highID.To15() != last || len(bounds) == 1 {
+		bounds = append(bounds, highID.To15())
+	}
+	return bounds, nil
+}
+
+func decodeBase62(s string) *big.Int {
+	n := new(big.Int)
+	base := big.NewInt(62)
+	for i := 0; i < len(s); i++ {
+		n.Mul(n, base)
+		n.Add(n, big.NewInt(int64(strings.IndexByte(base62Digits, s[i]))))
+	}
+	return n
+}
+
+func encodeBase62(n *big.Int, width int) string {
+	out := make([]byte, width)
+	v := new(big.Int).Set(n)
+	base := big.NewInt(62)
+	mod := new(big.Int)
+	for i := width - 1; i >= 0; i-- {
+		v.DivMod(v, base, mod)
+		out[i] = base62Digits[mod.Int64()]
+	}
+	return string(out)
+}
+
+// dateBounds divides the range between two datetimes evenly, at whole seconds.
+func dateBounds(low, high string, chunks int) ([]interface{}, error) {
+	lo, err := types.ParseDateTime(low)
+	if err != nil {
+		return nil, err
+	}
+	hi, err := types.ParseDateTime(high)
+	if err != nil {
+		return nil, err
+	}
+	start, end := lo.UTC().Truncate(time.Second), hi.UTC().Truncate(time.Second)
+	step := end.Sub(start) / time.Duration(chunks)
+	if step < time.Second {
+		step = time.Second
+	}
+	bounds := []interface{}{start}
+	for t := start.Add(step).Truncate(time.Second); t.Before(end); t = t.Add(step).Truncate(time.Second) {
+		bounds = append(bounds, t)
+	}
+	return append(bounds, end), nil
+}
@@ -0,0 +1,121 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxOffset is the largest OFFSET Salesforce accepts.
+const MaxOffset = 2000
+
+// Scope is the USING SCOPE filter of a query.
+type Scope string
+
+const (
+	ScopeDelegated       Scope = "delegated"
+	ScopeEverything      Scope = "everything"
+	ScopeMine            Scope = "mine"
+	ScopeMineAndMyGroups Scope = "mine_and_my_groups"
+	ScopeMyTerritory     Scope = "my_territory"
+	ScopeMyTeamTerritory Scope = "my_team_territory"
+	ScopeTeam            Scope = "team"
+)
+
+// Security is the WITH clause controlling field- and object-level security.
+type Security string
+
+const (
+	SecurityEnforced Security = "SECURITY_ENFORCED"
+	UserMode         Security = "USER_MODE"
+	SystemMode       Security = "SYSTEM_MODE"
+)
+
+// UsingScope adds USING SCOPE to limit records by ownership or territory.
+func (b *Builder) UsingScope(scope Scope) *Builder {
+	b.scope = scope
+	return b
+}
+
+// WithSecurityEnforced adds WITH SECURITY_ENFORCED.
+func (b *Builder) WithSecurityEnforced() *Builder {
+	return b.addSecurity(SecurityEnforced)
+}
+
+// WithUserMode adds WITH USER_MODE.
+func (b *Builder) WithUserMode() *Builder {
+	return b.addSecurity(UserMode)
+}
+
+// WithSystemMode adds WITH SYSTEM_MODE.
+func (b *Builder) WithSystemMode() *Builder {
+	return b.addSecurity(SystemMode)
+}
+
+func (b *Builder) addSecurity(mode Security) *Builder {
+	for _, m := range b.security {
+		if m == mode {
+			return b
+		}
+	}
+	b.security = append(b.security, mode)
+	return b
+}
+
+// validateClauses checks combinations of clauses that Salesforce rejects.
+func (b *Builder) validateClauses() error {
+	if len(b.security) > 1 {
+		return fmt.Errorf("only one WITH security clause is allowed, got %d", len(b.security))
+	}
+	if b.offset > MaxOffset {
+		return fmt.Errorf("OFFSET %d exceeds the maximum of %d", b.offset, MaxOffset)
+	}
+	if err := b.validateGroupBy(); err != nil {
+		return err
+	}
+	for _, f := range b.fields {
+		upper := strings.ToUpper(f)
+		if upper == "FIELDS(ALL)" || upper == "FIELDS(CUSTOM)" {
+			if b.limit <= 0 || b.limit > MaxFieldsLimit {
+				return fmt.Errorf("%s requires a LIMIT of at most %d", f, MaxFieldsLimit)
+			}
+		}
+	}
+	if b.forUpdate {
+		if len(b.orderBy) > 0 {
+			return errors.New("FOR UPDATE cannot be used with ORDER BY")
+		}
+		if b.forView || b.forRef {
+			return errors.New("FOR UPDATE cannot be combined with FOR VIEW or FOR REFERENCE")
+		}
+		if len(b.groupBy) > 0 || b.hasAggregate() {
+			return errors.New("FOR UPDATE cannot be used with aggregate queries")
+		}
+		if len(b.subqueries) > 0 {
+			return errors.New("FOR UPDATE cannot be used with subqueries")
+		}
+	}
+	if b.forView && b.forRef {
+		return errors.New("FOR VIEW and FOR REFERENCE cannot be combined")
+	}
+	for _, sub := range b.subqueries {
+		if sub != nil && (sub.forUpdate || sub.forView || sub.forRef || len(sub.security) > 0 || sub.scope != "") {
+			return fmt.Errorf("subquery on %s cannot use FOR, WITH or USING SCOPE clauses", sub.objectType)
+		}
+	}
+	return nil
+}
+
+var aggregateFunctions = []string{"COUNT(", "COUNT_DISTINCT(", "SUM(", "AVG(", "MIN(", "MAX("}
+
+func (b *Builder) hasAggregate() bool {
+	for _, f := range b.fields {
+		upper := strings.ToUpper(f)
+		for _, fn := range aggregateFunctions {
+			if strings.HasPrefix(upper, fn) {
+				return true
+			}
+		}
+	}
+	return false
+}
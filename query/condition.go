@@ -0,0 +1,246 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Condition is a composable SOQL boolean expression usable in WHERE and HAVING.
+type Condition interface {
+	// SOQL renders the condition.
+	SOQL() string
+}
+
+type rawCondition string
+
+func (r rawCondition) SOQL() string { return string(r) }
+
+type comparison struct {
+	field    string
+	operator string
+	value    string
+}
+
+func (c comparison) SOQL() string {
+	return c.field + " " + c.operator + " " + c.value
+}
+
+type junction struct {
+	operator   string
+	conditions []Condition
+}
+
+func (j junction) SOQL() string {
+	s, _ := render(j)
+	return s
+}
+
+type negation struct {
+	condition Condition
+}
+
+func (n negation) SOQL() string {
+	s, _ := render(n)
+	return s
+}
+
+// render renders a condition once, returning its text and the operator
+// that binds it at the top level: AND or OR for a junction of several
+// conditions, NOT for a negation, or "" when it needs no parentheses. A
+// junction with a single non-empty condition takes that condition's
+// operator, so nesting is decided by the condition actually rendered.
+// Each child is rendered exactly once, keeping deep nesting linear.
+func render(c Condition) (string, string) {
+	switch v := c.(type) {
+	case nil:
+		return "", ""
+	case junction:
+		parts := make([]string, 0, len(v.conditions))
+		var first, firstOp string
+		for _, child := range v.conditions {
+			s, op := render(child)
+			if s == "" {
+				continue
+			}
+			if len(parts) == 0 {
+				first, firstOp = s, op
+			}
+			// AND within AND and OR within OR need no parentheses.
+			if op != "" && op != v.operator {
+				s = "(" + s + ")"
+			}
+			parts = append(parts, s)
+		}
+		switch len(parts) {
+		case 0:
+			return "", ""
+		case 1:
+			return first, firstOp
+		}
+		return strings.Join(parts, " "+v.operator+" "), v.operator
+	case negation:
+		s, op := render(v.condition)
+		if s == "" {
+			return "", ""
+		}
+		if op != "" {
+			s = "(" + s + ")"
+		}
+		return "NOT " + s, "NOT"
+	case rawCondition:
+		// Raw conditions may contain their own AND/OR.
+		upper := strings.ToUpper(string(v))
+		if strings.Contains(upper, " AND ") || strings.Contains(upper, " OR ") {
+			return string(v), "RAW"
+		}
+		return string(v), ""
+	}
+	return c.SOQL(), ""
+}
+
+// isEmpty reports whether a condition renders as nothing, without
+// rendering junctions and negations.
+func isEmpty(c Condition) bool {
+	switch v := c.(type) {
+	case nil:
+		return true
+	case junction:
+		for _, child := range v.conditions {
+			if !isEmpty(child) {
+				return false
+			}
+		}
+		return true
+	case negation:
+		return isEmpty(v.condition)
+	}
+	return c.SOQL() == ""
+}
+
+func countNonEmpty(conditions []Condition) int {
+	n := 0
+	for _, c := range conditions {
+		if !isEmpty(c) {
+			n++
+		}
+	}
+	return n
+}
+
+// Raw wraps a hand-written condition. Values in it are not escaped.
+func Raw(condition string) Condition {
+	return rawCondition(condition)
+}
+
+// And combines conditions with AND.
+func And(conditions ...Condition) Condition {
+	return junction{operator: "AND", conditions: conditions}
+}
+
+// Or combines conditions with OR.
+func Or(conditions ...Condition) Condition {
+	return junction{operator: "OR", conditions: conditions}
+}
+
+// Not negates a condition. Negating nil or an empty condition yields an
+// empty condition.
+func Not(condition Condition) Condition {
+	return negation{condition: condition}
+}
+
+// Equals returns a field = value condition.
+func Equals(field string, value interface{}) Condition {
+	return comparison{field: field, operator: "=", value: formatValue(value)}
+}
+
+// NotEquals returns a field != value condition.
+func NotEquals(field string, value interface{}) Condition {
+	return comparison{field: field, operator: "!=", value: formatValue(value)}
+}
+
+// GreaterThan returns a field > value condition.
+func GreaterThan(field string, value interface{}) Condition {
+	return comparison{field: field, operator: ">", value: formatValue(value)}
+}
+
+// GreaterOrEqual returns a field >= value condition.
+func GreaterOrEqual(field string, value interface{}) Condition {
+	return comparison{field: field, operator: ">=", value: formatValue(value)}
+}
+
+// LessThan returns a field < value condition.
+func LessThan(field string, value interface{}) Condition {
+	return comparison{field: field, operator: "<", value: formatValue(value)}
+}
+
+// LessOrEqual returns a field <= value condition.
+func LessOrEqual(field string, value interface{}) Condition {
+	return comparison{field: field, operator: "<=", value: formatValue(value)}
+}
+
+// In returns a field IN (values) condition. A single *Builder value
+// produces a semi-join. With no values it returns an empty condition,
+// which AND and OR skip, so it places no restriction on the query.
+func In(field string, values ...interface{}) Condition {
+	if len(values) == 0 {
+		return rawCondition("")
+	}
+	if sub, ok := singleBuilder(values); ok {
+		return InQuery(field, sub)
+	}
+	return comparison{field: field, operator: "IN", value: formatList(values)}
+}
+
+// NotIn returns a field NOT IN (values) condition. A single *Builder
+// value produces an anti-join. With no values it returns an empty
+// condition.
+func NotIn(field string, values ...interface{}) Condition {
+	if len(values) == 0 {
+		return rawCondition("")
+	}
+	if sub, ok := singleBuilder(values); ok {
+		return NotInQuery(field, sub)
+	}
+	return comparison{field: field, operator: "NOT IN", value: formatList(values)}
+}
+
+// Like returns a field LIKE pattern condition.
+func Like(field, pattern string) Condition {
+	return comparison{field: field, operator: "LIKE", value: fmt.Sprintf("'%s'", escapeSoql(pattern))}
+}
+
+// IsNull returns a field = NULL condition.
+func IsNull(field string) Condition {
+	return comparison{field: field, operator: "=", value: "NULL"}
+}
+
+// IsNotNull returns a field != NULL condition.
+func IsNotNull(field string) Condition {
+	return comparison{field: field, operator: "!=", value: "NULL"}
+}
+
+// Includes returns a multi-select picklist INCLUDES condition.
+func Includes(field string, values ...string) Condition {
+	return comparison{field: field, operator: "INCLUDES", value: formatStrings(values)}
+}
+
+// Excludes returns a multi-select picklist EXCLUDES condition.
+func Excludes(field string, values ...string) Condition {
+	return comparison{field: field, operator: "EXCLUDES", value: formatStrings(values)}
+}
+
+func formatList(values []interface{}) string {
+	formatted := make([]string, len(values))
+	for i, v := range values {
+		formatted[i] = formatValue(v)
+	}
+	return "(" + strings.Join(formatted, ", ") + ")"
+}
+
+func formatStrings(values []string) string {
+	formatted := make([]string, len(values))
+	for i, v := range values {
+		formatted[i] = formatValue(v)
+	}
+	return "(" + strings.Join(formatted, ", ") + ")"
+}
@@ -0,0 +1,75 @@
+package query
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSingleChildJunctions(t *testing.T) {
+	a, b, c := Equals("A", 1), Equals("B", 2), Equals("C", 3)
+	tests := []struct {
+		name string
+		cond Condition
+		want string
+	}{
+		{"and of single-child and of or", And(And(Or(a, b)), c), "(A = 1 OR B = 2) AND C = 3"},
+		{"or of single-child or of and", Or(Or(And(a, b)), c), "(A = 1 AND B = 2) OR C = 3"},
+		{"and of single-child or of or", And(Or(Or(a, b)), c), "(A = 1 OR B = 2) AND C = 3"},
+		{"and of single-child or of and", And(Or(And(a, b)), c), "A = 1 AND B = 2 AND C = 3"},
+		{"not of single-child and of or", Not(And(Or(a, b))), "NOT (A = 1 OR B = 2)"},
+		{"not of single-child or of and", Not(Or(And(a, b))), "NOT (A = 1 AND B = 2)"},
+		{"not of single-child and of comparison", Not(And(a)), "NOT A = 1"},
+		{"single-child and with nil and empty", And(nil, Or(a, b), And(), c), "(A = 1 OR B = 2) AND C = 3"},
+		{"single-child and of raw", And(And(Raw("A = 1 OR B = 2")), c), "(A = 1 OR B = 2) AND C = 3"},
+		{"top-level single-child and", And(Or(a, b)), "A = 1 OR B = 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cond.SOQL(); got != tt.want {
+				t.Errorf("SOQL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmptyConditions(t *testing.T) {
+	c := Equals("C", 3)
+	tests := []struct {
+		name string
+		cond Condition
+		want string
+	}{
+		{"not of nil", Not(nil), ""},
+		{"not of empty and", Not(And()), ""},
+		{"in without values", In("Id"), ""},
+		{"not in without values", NotIn("Id"), ""},
+		{"and skips empty in", And(In("Id"), c), "C = 3"},
+		{"or skips empty not", Or(Not(nil), c), "C = 3"},
+		{"not of not", Not(Not(c)), "NOT (NOT C = 3)"},
+		{"negation inside and", And(Not(c), c), "(NOT C = 3) AND C = 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cond.SOQL(); got != tt.want {
+				t.Errorf("SOQL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeepNesting(t *testing.T) {
+	// Each level used to render its children several times, so a few
+	// dozen levels took exponential time.
+	c := Equals("A", 0)
+	want := "A = 0"
+	for i := 1; i <= 200; i++ {
+		c = And(Or(c, Equals("A", i)))
+		want = fmt.Sprintf("%s OR A = %d", want, i)
+	}
+	if got := c.SOQL(); got != want {
+		t.Errorf("SOQL() = %q, want %q", got, want)
+	}
+	if got := And(c, Equals("B", 1)).SOQL(); got != "("+want+") AND B = 1" {
+		t.Errorf("SOQL() = %q", got)
+	}
+}
@@ -0,0 +1,102 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/PramithaMJ/salesforce/v2/types"
+)
+
+// Leading operation types of a query plan.
+const (
+	OperationIndex     = "Index"
+	OperationOther     = "Other"
+	OperationSharing   = "Sharing"
+	OperationTableScan = "TableScan"
+)
+
+// PlanNote explains why an index could not be used.
+type PlanNote struct {
+	Description   string   `json:"description"`
+	Fields        []string `json:"fields"`
+	TableEnumOrID string   `json:"tableEnumOrId"`
+}
+
+// Plan is a candidate execution plan for a query.
+type Plan struct {
+	Cardinality          int        `json:"cardinality"`
+	Fields               []string   `json:"fields"`
+	LeadingOperationType string     `json:"leadingOperationType"`
+	Notes                []PlanNote `json:"notes"`
+	RelativeCost         float64    `json:"relativeCost"`
+	SObjectCardinality   int        `json:"sobjectCardinality"`
+	SObjectType          string     `json:"sobjectType"`
+}
+
+// IsTableScan returns true if the plan scans every record of the object.
+func (p *Plan) IsTableScan() bool {
+	return p.LeadingOperationType == OperationTableScan
+}
+
+// IsSelective returns true if the plan's relative cost is below 1, the
+// threshold Salesforce uses to pick an index over a full scan.
+func (p *Plan) IsSelective() bool {
+	return p.RelativeCost < 1
+}
+
+// Explanation contains the plans Salesforce considered for a query,
+// ordered from lowest to highest relative cost.
+type Explanation struct {
+	Plans       []Plan `json:"plans"`
+	SourceQuery string `json:"sourceQuery,omitempty"`
+}
+
+// Best returns the plan Salesforce will use, or nil if there is none.
+func (e *Explanation) Best() *Plan {
+	if len(e.Plans) == 0 {
+		return nil
+	}
+	return &e.Plans[0]
+}
+
+// Explain returns the query plans for a SOQL query without running it.
+func (s *Service) Explain(ctx context.Context, query string) (*Explanation, error) {
+	path := fmt.Sprintf("/services/data/v%s/query?explain=%s", s.apiVersion, url.QueryEscape(query))
+	respBody, err := s.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var explanation Explanation
+	if err := json.Unmarshal(respBody, &explanation); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &explanation, nil
+}
+
+// WarnOnTableScan makes Execute explain each query first and log a warning
+// when its best plan is a table scan. Pass nil to disable.
+func (s *Service) WarnOnTableScan(logger types.Logger) {
+	s.explainLogger = logger
+}
+
+func (s *Service) checkPlan(ctx context.Context, query string) {
+	logger := s.explainLogger
+	if logger == nil {
+		return
+	}
+	explanation, err := s.Explain(ctx, query)
+	if err != nil {
+		logger.Debug("Query explain failed", "query", query, "error", err)
+		return
+	}
+	if plan := explanation.Best(); plan != nil && plan.IsTableScan() {
+		logger.Warn("Query will use a table scan",
+			"sobjectType", plan.SObjectType,
+			"cardinality", plan.Cardinality,
+			"sobjectCardinality", plan.SObjectCardinality,
+			"relativeCost", plan.RelativeCost,
+			"query", query)
+	}
+}
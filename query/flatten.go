@@ -0,0 +1,298 @@
+package query
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/PramithaMJ/salesforce/v2/export"
+)
+
+// ChildMode controls how parent-to-child subquery results are flattened.
+type ChildMode int
+
+const (
+	// ChildRows produces one row per child record, repeating the parent's
+	// values, with child fields in columns such as Contacts.Name. A parent
+	// without children produces one row with nil child columns. Several
+	// subqueries produce every combination of their children.
+	ChildRows ChildMode = iota
+	// ChildCount puts the number of child records in a single column named
+	// after the relationship.
+	ChildCount
+	// ChildJSON puts the flattened child records in a single column named
+	// after the relationship. Writers encode them as a JSON array.
+	ChildJSON
+)
+
+// FlattenOptions configures Flatten.
+type FlattenOptions struct {
+	// Children controls how subquery results are flattened. Defaults to ChildRows.
+	Children ChildMode
+}
+
+// Flatten turns a record into rows keyed by dotted field paths such as
+// Account.Owner.Name, dropping attributes blocks. It returns several rows
+// only for ChildRows with subquery results.
+func Flatten(record *SObject, opts *FlattenOptions) []map[string]interface{} {
+	if opts == nil {
+		opts = &FlattenOptions{}
+	}
+	row := make(map[string]interface{})
+	var children []childRows
+	flattenInto(row, "", record.ToMap(), opts.Children, &children)
+	rows := []map[string]interface{}{row}
+	for _, child := range children {
+		rows = expandChildren(rows, child)
+	}
+	return rows
+}
+
+type childRows struct {
+	relationship string
+	rows         []map[string]interface{}
+}
+
+func flattenInto(row map[string]interface{}, prefix string, data map[string]interface{}, mode ChildMode, children *[]childRows) {
+	for key, value := range data {
+		if key == "attributes" {
+			continue
+		}
+		path := prefix + key
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			row[path] = value
+			continue
+		}
+		records, isChild := childRecords(nested)
+		if !isChild {
+			flattenInto(row, path+".", nested, mode, children)
+			continue
+		}
+		switch mode {
+		case ChildCount:
+			row[path] = len(records)
+		case ChildJSON:
+			flat := make([]map[string]interface{}, 0, len(records))
+			for _, r := range records {
+				flat = append(flat, Flatten(FromMap(r), &FlattenOptions{Children: ChildJSON})...)
+			}
+			row[path] = flat
+		default:
+			child := childRows{relationship: path}
+			for _, r := range records {
+				for _, flat := range Flatten(FromMap(r), nil) {
+					prefixed := make(map[string]interface{}, len(flat))
+					for k, v := range flat {
+						prefixed[path+"."+k] = v
+					}
+					child.rows = append(child.rows, prefixed)
+				}
+			}
+			*children = append(*children, child)
+		}
+	}
+}
+
+// childRecords returns the records of a subquery result, which has the
+// same shape as a top-level query result.
+func childRecords(m map[string]interface{}) ([]map[string]interface{}, bool) {
+	raw, ok := m["records"].([]interface{})
+	if !ok {
+		return nil, false
+	}
+	if _, ok := m["totalSize"]; !ok {
+		return nil, false
+	}
+	records := make([]map[string]interface{}, 0, len(raw))
+	for _, r := range raw {
+		if rec, ok := r.(map[string]interface{}); ok {
+			records = append(records, rec)
+		}
+	}
+	return records, true
+}
+
+func expandChildren(rows []map[string]interface{}, child childRows) []map[string]interface{} {
+	if len(child.rows) == 0 {
+		return rows
+	}
+	out := make([]map[string]interface{}, 0, len(rows)*len(child.rows))
+	for _, row := range rows {
+		for _, c := range child.rows {
+			merged := make(map[string]interface{}, len(row)+len(c))
+			for k, v := range row {
+				merged[k] = v
+			}
+			for k, v := range c {
+				merged[k] = v
+			}
+			out = append(out, merged)
+		}
+	}
+	return out
+}
+
+// Columns returns the flattened column names of the query's results in
+// SELECT order, as Flatten produces them. Unaliased aggregate and date
+// functions are named expr0, expr1 and so on, and grouped relationship
+// fields by their last name, as Salesforce returns them. It returns nil
+// for queries using FIELDS(), whose columns are only known from results.
+func (q *Query) Columns(opts *FlattenOptions) []string {
+	if opts == nil {
+		opts = &FlattenOptions{}
+	}
+	aggregate := len(q.GroupBy) > 0 || q.Builder().hasAggregate()
+	var columns []string
+	expr := 0
+	for _, item := range q.Select {
+		switch v := item.Expr.(type) {
+		case *Field:
+			name := v.Name
+			if aggregate {
+				name = name[strings.LastIndex(name, ".")+1:]
+			}
+			columns = append(columns, aliasOr(item.Alias, name))
+		case *Func:
+			name := strings.ToUpper(v.Name)
+			switch {
+			case name == "FIELDS":
+				return nil
+			case name == "COUNT" && len(v.Args) == 0:
+				continue
+			case item.Alias != "":
+				columns = append(columns, item.Alias)
+			case (name == "TOLABEL" || name == "CONVERTCURRENCY" || name == "FORMAT") && len(v.Args) > 0:
+				columns = append(columns, v.Args[0].String())
+			default:
+				columns = append(columns, ExprName(expr))
+				expr++
+			}
+		case *TypeOfExpr:
+			columns = append(columns, typeOfColumns(v)...)
+		case *Query:
+			relationship := v.From.Name
+			if opts.Children != ChildRows {
+				columns = append(columns, relationship)
+				continue
+			}
+			for _, col := range v.Columns(nil) {
+				columns = append(columns, relationship+"."+col)
+			}
+		}
+	}
+	return columns
+}
+
+func aliasOr(alias, name string) string {
+	if alias != "" {
+		return alias
+	}
+	return name
+}
+
+func typeOfColumns(t *TypeOfExpr) []string {
+	var columns []string
+	seen := make(map[string]bool)
+	add := func(fields []*Field) {
+		for _, f := range fields {
+			col := t.Field.Name + "." + f.Name
+			if !seen[strings.ToLower(col)] {
+				seen[strings.ToLower(col)] = true
+				columns = append(columns, col)
+			}
+		}
+	}
+	for _, when := range t.Whens {
+		add(when.Fields)
+	}
+	add(t.Else)
+	return columns
+}
+
+// ExportOptions configures Export.
+type ExportOptions struct {
+	// Children controls how subquery results are flattened.
+	Children ChildMode
+	// BatchSize is passed to the query iterator.
+	BatchSize int
+	// IncludeDeleted runs the query through queryAll.
+	IncludeDeleted bool
+	// Columns are the columns of a FIELDS() query, whose columns otherwise
+	// come from the first record. Without them, a FIELDS() query that
+	// returns no rows has no header, which writers that need a schema,
+	// such as export.ParquetWriter, reject.
+	Columns []string
+}
+
+// Export runs a query and writes its flattened records to w, returning the
+// number of rows written. Columns come from the query's SELECT list, so
+// null parent relationships still produce their columns; for FIELDS()
+// queries they are opts.Columns or the sorted columns of the first record.
+// A query with no rows writes just the header. Column names
+// are matched to record fields case-insensitively. Export closes w.
+func (s *Service) Export(ctx context.Context, soql string, w export.Writer, opts *ExportOptions) (count int, err error) {
+	defer func() {
+		if cerr := w.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+	q, err := Parse(soql)
+	if err != nil {
+		return 0, err
+	}
+	flatten := &FlattenOptions{Children: opts.Children}
+	columns := q.Columns(flatten)
+	if columns == nil && len(opts.Columns) > 0 {
+		columns = opts.Columns
+	}
+
+	it := s.Iterate(ctx, soql, &IteratorOptions{BatchSize: opts.BatchSize, IncludeDeleted: opts.IncludeDeleted})
+	defer it.Close()
+	values := make([]interface{}, len(columns))
+	for it.Next() {
+		for _, row := range Flatten(it.Record(), flatten) {
+			if count == 0 {
+				if columns == nil {
+					columns = sortedKeys(row)
+					values = make([]interface{}, len(columns))
+				}
+				if err := w.WriteHeader(columns); err != nil {
+					return count, err
+				}
+			}
+			lower := make(map[string]interface{}, len(row))
+			for k, v := range row {
+				lower[strings.ToLower(k)] = v
+			}
+			for i, col := range columns {
+				values[i] = lower[strings.ToLower(col)]
+			}
+			if err := w.WriteRow(values); err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+	if err := it.Err(); err != nil {
+		return count, err
+	}
+	if count == 0 && columns != nil {
+		if err := w.WriteHeader(columns); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+func sortedKeys(row map[string]interface{}) []string {
+	keys := make([]string, 0, len(row))
+	for k := range row {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,237 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DateLiteral is a relative SOQL date literal such as TODAY or LAST_N_DAYS:30.
+// It is written into queries unquoted.
+type DateLiteral string
+
+// Fixed date literals.
+const (
+	Yesterday         DateLiteral = "YESTERDAY"
+	Today             DateLiteral = "TODAY"
+	Tomorrow          DateLiteral = "TOMORROW"
+	LastWeek          DateLiteral = "LAST_WEEK"
+	ThisWeek          DateLiteral = "THIS_WEEK"
+	NextWeek          DateLiteral = "NEXT_WEEK"
+	LastMonth         DateLiteral = "LAST_MONTH"
+	ThisMonth         DateLiteral = "THIS_MONTH"
+	NextMonth         DateLiteral = "NEXT_MONTH"
+	Last90Days        DateLiteral = "LAST_90_DAYS"
+	Next90Days        DateLiteral = "NEXT_90_DAYS"
+	LastQuarter       DateLiteral = "LAST_QUARTER"
+	ThisQuarter       DateLiteral = "THIS_QUARTER"
+	NextQuarter       DateLiteral = "NEXT_QUARTER"
+	LastYear          DateLiteral = "LAST_YEAR"
+	ThisYear          DateLiteral = "THIS_YEAR"
+	NextYear          DateLiteral = "NEXT_YEAR"
+	LastFiscalQuarter DateLiteral = "LAST_FISCAL_QUARTER"
+	ThisFiscalQuarter DateLiteral = "THIS_FISCAL_QUARTER"
+	NextFiscalQuarter DateLiteral = "NEXT_FISCAL_QUARTER"
+	LastFiscalYear    DateLiteral = "LAST_FISCAL_YEAR"
+	ThisFiscalYear    DateLiteral = "THIS_FISCAL_YEAR"
+	NextFiscalYear    DateLiteral = "NEXT_FISCAL_YEAR"
+)
+
+// LastNDays returns the LAST_N_DAYS:n date literal.
+func LastNDays(n int) DateLiteral { return dateLiteralN("LAST_N_DAYS", n) }
+
+// NextNDays returns the NEXT_N_DAYS:n date literal.
+func NextNDays(n int) DateLiteral { return dateLiteralN("NEXT_N_DAYS", n) }
+
+// NDaysAgo returns the N_DAYS_AGO:n date literal.
+func NDaysAgo(n int) DateLiteral { return dateLiteralN("N_DAYS_AGO", n) }
+
+// LastNWeeks returns the LAST_N_WEEKS:n date literal.
+func LastNWeeks(n int) DateLiteral { return dateLiteralN("LAST_N_WEEKS", n) }
+
+// NextNWeeks returns the NEXT_N_WEEKS:n date literal.
+func NextNWeeks(n int) DateLiteral { return dateLiteralN("NEXT_N_WEEKS", n) }
+
+// LastNMonths returns the LAST_N_MONTHS:n date literal.
+func LastNMonths(n int) DateLiteral { return dateLiteralN("LAST_N_MONTHS", n) }
+
+// NextNMonths returns the NEXT_N_MONTHS:n date literal.
+func NextNMonths(n int) DateLiteral { return dateLiteralN("NEXT_N_MONTHS", n) }
+
+// LastNQuarters returns the LAST_N_QUARTERS:n date literal.
+func LastNQuarters(n int) DateLiteral { return dateLiteralN("LAST_N_QUARTERS", n) }
+
+// NextNQuarters returns the NEXT_N_QUARTERS:n date literal.
+func NextNQuarters(n int) DateLiteral { return dateLiteralN("NEXT_N_QUARTERS", n) }
+
+// LastNYears returns the LAST_N_YEARS:n date literal.
+func LastNYears(n int) DateLiteral { return dateLiteralN("LAST_N_YEARS", n) }
+
+// NextNYears returns the NEXT_N_YEARS:n date literal.
+func NextNYears(n int) DateLiteral { return dateLiteralN("NEXT_N_YEARS", n) }
+
+// LastNFiscalQuarters returns the LAST_N_FISCAL_QUARTERS:n date literal.
+func LastNFiscalQuarters(n int) DateLiteral { return dateLiteralN("LAST_N_FISCAL_QUARTERS", n) }
+
+// NextNFiscalQuarters returns the NEXT_N_FISCAL_QUARTERS:n date literal.
+func NextNFiscalQuarters(n int) DateLiteral { return dateLiteralN("NEXT_N_FISCAL_QUARTERS", n) }
+
+// LastNFiscalYears returns the LAST_N_FISCAL_YEARS:n date literal.
+func LastNFiscalYears(n int) DateLiteral { return dateLiteralN("LAST_N_FISCAL_YEARS", n) }
+
+// NextNFiscalYears returns the NEXT_N_FISCAL_YEARS:n date literal.
+func NextNFiscalYears(n int) DateLiteral { return dateLiteralN("NEXT_N_FISCAL_YEARS", n) }
+
+func dateLiteralN(name string, n int) DateLiteral {
+	return DateLiteral(fmt.Sprintf("%s:%d", name, n))
+}
+
+// Aggregate functions.
+
+// Count returns COUNT(field), or COUNT() when field is empty.
+func Count(field string) string { return "COUNT(" + field + ")" }
+
+// CountDistinct returns COUNT_DISTINCT(field).
+func CountDistinct(field string) string { return "COUNT_DISTINCT(" + field + ")" }
+
+// Sum returns SUM(field).
+func Sum(field string) string { return "SUM(" + field + ")" }
+
+// Avg returns AVG(field).
+func Avg(field string) string { return "AVG(" + field + ")" }
+
+// Min returns MIN(field).
+func Min(field string) string { return "MIN(" + field + ")" }
+
+// Max returns MAX(field).
+func Max(field string) string { return "MAX(" + field + ")" }
+
+// Date functions.
+
+// CalendarMonth returns CALENDAR_MONTH(field).
+func CalendarMonth(field string) string { return "CALENDAR_MONTH(" + field + ")" }
+
+// CalendarQuarter returns CALENDAR_QUARTER(field).
+func CalendarQuarter(field string) string { return "CALENDAR_QUARTER(" + field + ")" }
+
+// CalendarYear returns CALENDAR_YEAR(field).
+func CalendarYear(field string) string { return "CALENDAR_YEAR(" + field + ")" }
+
+// DayInMonth returns DAY_IN_MONTH(field).
+func DayInMonth(field string) string { return "DAY_IN_MONTH(" + field + ")" }
+
+// DayInWeek returns DAY_IN_WEEK(field).
+func DayInWeek(field string) string { return "DAY_IN_WEEK(" + field + ")" }
+
+// DayInYear returns DAY_IN_YEAR(field).
+func DayInYear(field string) string { return "DAY_IN_YEAR(" + field + ")" }
+
+// DayOnly returns DAY_ONLY(field).
+func DayOnly(field string) string { return "DAY_ONLY(" + field + ")" }
+
+// FiscalMonth returns FISCAL_MONTH(field).
+func FiscalMonth(field string) string { return "FISCAL_MONTH(" + field + ")" }
+
+// FiscalQuarter returns FISCAL_QUARTER(field).
+func FiscalQuarter(field string) string { return "FISCAL_QUARTER(" + field + ")" }
+
+// FiscalYear returns FISCAL_YEAR(field).
+func FiscalYear(field string) string { return "FISCAL_YEAR(" + field + ")" }
+
+// HourInDay returns HOUR_IN_DAY(field).
+func HourInDay(field string) string { return "HOUR_IN_DAY(" + field + ")" }
+
+// WeekInMonth returns WEEK_IN_MONTH(field).
+func WeekInMonth(field string) string { return "WEEK_IN_MONTH(" + field + ")" }
+
+// WeekInYear returns WEEK_IN_YEAR(field).
+func WeekInYear(field string) string { return "WEEK_IN_YEAR(" + field + ")" }
+
+// ConvertTimezone returns convertTimezone(field), for use inside date functions.
+func ConvertTimezone(field string) string { return "convertTimezone(" + field + ")" }
+
+// Field formatting functions.
+
+// ToLabel returns toLabel(field), selecting translated picklist labels.
+func ToLabel(field string) string { return "toLabel(" + field + ")" }
+
+// ConvertCurrency returns convertCurrency(field), selecting the value in the user's currency.
+func ConvertCurrency(field string) string { return "convertCurrency(" + field + ")" }
+
+// Format returns FORMAT(field), selecting the value formatted for the user's locale.
+func Format(field string) string { return "FORMAT(" + field + ")" }
+
+// SelectAs selects an expression, such as an aggregate, under an alias.
+func (b *Builder) SelectAs(expr, alias string) *Builder {
+	return b.Select(expr + " " + alias)
+}
+
+// FieldSet is the argument of the FIELDS() select function.
+type FieldSet string
+
+const (
+	FieldsAll      FieldSet = "ALL"
+	FieldsStandard FieldSet = "STANDARD"
+	FieldsCustom   FieldSet = "CUSTOM"
+)
+
+// MaxFieldsLimit is the largest LIMIT allowed with FIELDS(ALL) and FIELDS(CUSTOM).
+const MaxFieldsLimit = 200
+
+// SelectFields selects FIELDS(ALL), FIELDS(STANDARD) or FIELDS(CUSTOM).
+func (b *Builder) SelectFields(set FieldSet) *Builder {
+	return b.Select("FIELDS(" + string(set) + ")")
+}
+
+// TypeOf is a TYPEOF expression selecting different fields depending on
+// the type of a polymorphic relationship.
+type TypeOf struct {
+	field    string
+	whens    []typeOfWhen
+	elseFlds []string
+}
+
+type typeOfWhen struct {
+	objectType string
+	fields     []string
+}
+
+// NewTypeOf starts a TYPEOF expression on a polymorphic relationship, such as What.
+func NewTypeOf(field string) *TypeOf {
+	return &TypeOf{field: field}
+}
+
+// When selects fields when the related record is of objectType.
+func (t *TypeOf) When(objectType string, fields ...string) *TypeOf {
+	t.whens = append(t.whens, typeOfWhen{objectType: objectType, fields: fields})
+	return t
+}
+
+// Else selects fields for any other related object type.
+func (t *TypeOf) Else(fields ...string) *TypeOf {
+	t.elseFlds = append(t.elseFlds, fields...)
+	return t
+}
+
+// String renders the TYPEOF expression.
+func (t *TypeOf) String() string {
+	var sb strings.Builder
+	sb.WriteString("TYPEOF ")
+	sb.WriteString(t.field)
+	for _, w := range t.whens {
+		sb.WriteString(" WHEN ")
+		sb.WriteString(w.objectType)
+		sb.WriteString(" THEN ")
+		sb.WriteString(strings.Join(w.fields, ", "))
+	}
+	if len(t.elseFlds) > 0 {
+		sb.WriteString(" ELSE ")
+		sb.WriteString(strings.Join(t.elseFlds, ", "))
+	}
+	sb.WriteString(" END")
+	return sb.String()
+}
+
+// SelectTypeOf adds a TYPEOF expression to the selected fields.
+func (b *Builder) SelectTypeOf(t *TypeOf) *Builder {
+	return b.Select(t.String())
+}
@@ -0,0 +1,54 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// DistanceUnit is the unit used by the SOQL DISTANCE function.
+type DistanceUnit string
+
+const (
+	Miles      DistanceUnit = "mi"
+	Kilometers DistanceUnit = "km"
+)
+
+// Geolocation returns a SOQL GEOLOCATION(lat, lng) expression.
+func Geolocation(latitude, longitude float64) string {
+	return fmt.Sprintf("GEOLOCATION(%s, %s)", formatFloat(latitude), formatFloat(longitude))
+}
+
+// Distance returns a SOQL DISTANCE expression between a compound
+// geolocation or address field and a point.
+func Distance(field string, latitude, longitude float64, unit DistanceUnit) string {
+	return fmt.Sprintf("DISTANCE(%s, %s, '%s')", field, Geolocation(latitude, longitude), unit)
+}
+
+// SelectDistance selects the distance from a field to a point under an alias.
+func (b *Builder) SelectDistance(field string, latitude, longitude float64, unit DistanceUnit, alias string) *Builder {
+	expr := Distance(field, latitude, longitude, unit)
+	if alias != "" {
+		expr += " " + alias
+	}
+	return b.Select(expr)
+}
+
+// WhereDistanceLessThan keeps records within distance of a point.
+func (b *Builder) WhereDistanceLessThan(field string, latitude, longitude float64, unit DistanceUnit, distance float64) *Builder {
+	return b.Where(fmt.Sprintf("%s < %s", Distance(field, latitude, longitude, unit), formatFloat(distance)))
+}
+
+// WhereDistanceGreaterThan keeps records farther than distance from a point.
+func (b *Builder) WhereDistanceGreaterThan(field string, latitude, longitude float64, unit DistanceUnit, distance float64) *Builder {
+	return b.Where(fmt.Sprintf("%s > %s", Distance(field, latitude, longitude, unit), formatFloat(distance)))
+}
+
+// OrderByDistance orders records by distance from a point, nearest first.
+func (b *Builder) OrderByDistance(field string, latitude, longitude float64, unit DistanceUnit) *Builder {
+	b.orderBy = append(b.orderBy, Distance(field, latitude, longitude, unit)+" ASC")
+	return b
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,185 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	sfhttp "github.com/PramithaMJ/salesforce/v2/http"
+)
+
+// StreamingHTTPClient is implemented by HTTP clients that can set request
+// headers and stream response bodies. The SDK's http.Client satisfies it.
+type StreamingHTTPClient interface {
+	Do(ctx context.Context, req sfhttp.Request) (*sfhttp.Response, error)
+}
+
+// Page size limits of the Sforce-Query-Options batchSize header.
+const (
+	MinBatchSize = 200
+	MaxBatchSize = 2000
+)
+
+// IteratorOptions configures Iterate.
+type IteratorOptions struct {
+	// BatchSize asks Salesforce for pages of this many records, between
+	// MinBatchSize and MaxBatchSize. Salesforce treats it as a hint. It is
+	// ignored when the HTTP client cannot set headers.
+	BatchSize int
+	// IncludeDeleted runs the query through queryAll.
+	IncludeDeleted bool
+	// Prefetch is how many pages are fetched ahead of the caller. Defaults to 1.
+	Prefetch int
+}
+
+type page struct {
+	result *Result
+	err    error
+}
+
+// Iterator walks query results lazily, fetching the next page in the
+// background while the caller processes the current one. Always call
+// Close, typically with defer, to stop the background fetch.
+type Iterator struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	pages     chan page
+	stopped   chan struct{}
+	records   []*SObject
+	index     int
+	record    *SObject
+	totalSize int
+	err       error
+	finished  bool
+	closed    bool
+}
+
+// Iterate starts a query and returns an iterator over its records.
+func (s *Service) Iterate(ctx context.Context, query string, opts *IteratorOptions) *Iterator {
+	if opts == nil {
+		opts = &IteratorOptions{}
+	}
+	prefetch := opts.Prefetch
+	if prefetch <= 0 {
+		prefetch = 1
+	}
+	resource := "query"
+	if opts.IncludeDeleted {
+		resource = "queryAll"
+	}
+	path := fmt.Sprintf("/services/data/v%s/%s?q=%s", s.apiVersion, resource, url.QueryEscape(query))
+
+	ctx, cancel := context.WithCancel(ctx)
+	it := &Iterator{
+		ctx:     ctx,
+		cancel:  cancel,
+		pages:   make(chan page, prefetch),
+		stopped: make(chan struct{}),
+	}
+	go s.prefetch(ctx, path, opts.BatchSize, it.pages, it.stopped)
+	return it
+}
+
+func (s *Service) prefetch(ctx context.Context, path string, batchSize int, pages chan<- page, stopped chan<- struct{}) {
+	defer close(stopped)
+	defer close(pages)
+	for {
+		result, err := s.fetchPage(ctx, path, batchSize)
+		select {
+		case pages <- page{result: result, err: err}:
+		case <-ctx.Done():
+			return
+		}
+		if err != nil || !result.HasMore() {
+			return
+		}
+		path = result.NextRecordsURL
+	}
+}
+
+func (s *Service) fetchPage(ctx context.Context, path string, batchSize int) (*Result, error) {
+	sc, ok := s.client.(StreamingHTTPClient)
+	if !ok || batchSize <= 0 {
+		return s.executeQuery(ctx, path)
+	}
+	batchSize = max(MinBatchSize, min(batchSize, MaxBatchSize))
+	resp, err := sc.Do(ctx, sfhttp.Request{
+		Method:  http.MethodGet,
+		Path:    path,
+		Headers: map[string]string{"Sforce-Query-Options": fmt.Sprintf("batchSize=%d", batchSize)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return parseResult(respBody)
+}
+
+// Next advances to the next record. It returns false when the results are
+// exhausted, an error occurs or the iterator is closed.
+func (it *Iterator) Next() bool {
+	for {
+		if it.index < len(it.records) {
+			it.record = it.records[it.index]
+			it.index++
+			return true
+		}
+		it.record = nil
+		if it.finished {
+			return false
+		}
+		p, ok := <-it.pages
+		switch {
+		case !ok:
+			if err := it.ctx.Err(); err != nil && !it.closed {
+				it.err = err
+			}
+			it.finish()
+			return false
+		case p.err != nil:
+			it.err = p.err
+			it.finish()
+			return false
+		}
+		it.records, it.index = p.result.Records, 0
+		it.totalSize = p.result.TotalSize
+	}
+}
+
+// Record returns the current record.
+func (it *Iterator) Record() *SObject {
+	return it.record
+}
+
+// Err returns the error that stopped iteration, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// TotalSize returns the total number of records reported by Salesforce.
+// It is known once Next has been called.
+func (it *Iterator) TotalSize() int {
+	return it.totalSize
+}
+
+// Close stops the background fetch and waits for it to exit. It is safe
+// to call more than once.
+func (it *Iterator) Close() {
+	if !it.finished {
+		it.closed = true
+	}
+	it.finish()
+}
+
+func (it *Iterator) finish() {
+	it.finished = true
+	it.records = nil
+	it.cancel()
+	<-it.stopped
+}
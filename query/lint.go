@@ -0,0 +1,348 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/PramithaMJ/salesforce/v2/sobjects"
+	"github.com/PramithaMJ/salesforce/v2/types"
+)
+
+// Schema holds describe metadata used to lint queries offline, keyed by
+// lower-case object name.
+type Schema map[string]*sobjects.Metadata
+
+// NewSchema creates a schema from describe metadata.
+func NewSchema(objects ...*sobjects.Metadata) Schema {
+	s := make(Schema, len(objects))
+	for _, o := range objects {
+		s.Add(o)
+	}
+	return s
+}
+
+// Add adds or replaces the metadata of an object.
+func (s Schema) Add(meta *sobjects.Metadata) {
+	if meta != nil {
+		s[strings.ToLower(meta.Name)] = meta
+	}
+}
+
+// Object returns the metadata of an object, or nil if it is unknown.
+func (s Schema) Object(name string) *sobjects.Metadata {
+	return s[strings.ToLower(name)]
+}
+
+// LoadSchema reads saved describe JSON: a single describe response or an
+// array of them.
+func LoadSchema(r io.Reader) (Schema, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	var objects []*sobjects.Metadata
+	if len(data) > 0 && data[0] == '[' {
+		err = json.Unmarshal(data, &objects)
+	} else {
+		var meta sobjects.Metadata
+		err = json.Unmarshal(data, &meta)
+		objects = append(objects, &meta)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse describe metadata: %w", err)
+	}
+	return NewSchema(objects...), nil
+}
+
+// LoadSchemaFiles reads saved describe JSON files into one schema.
+func LoadSchemaFiles(paths ...string) (Schema, error) {
+	schema := Schema{}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		s, err := LoadSchema(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for k, v := range s {
+			schema[k] = v
+		}
+	}
+	return schema, nil
+}
+
+// Severity is the severity of a lint issue.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a problem found by Lint. Code is the error code Salesforce
+// would return for the same mistake.
+type Issue struct {
+	Pos      Pos
+	Severity Severity
+	Code     types.ErrorCode
+	Message  string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s: [%s] %s", i.Pos, i.Severity, i.Code, i.Message)
+}
+
+// Lint parses a query and checks it against the schema. A syntax error is
+// returned as a *ParseError.
+func Lint(soql string, schema Schema) ([]Issue, error) {
+	q, err := Parse(soql)
+	if err != nil {
+		return nil, err
+	}
+	return q.Lint(schema), nil
+}
+
+// Lint checks object, field and relationship names against the schema and
+// flags fields used where describe says they cannot be filtered, sorted,
+// grouped or aggregated. Relationships to objects missing from the schema
+// are not checked. Issues are ordered by position.
+func (q *Query) Lint(schema Schema) []Issue {
+	l := &linter{schema: schema}
+	if err := q.Builder().Validate(); err != nil {
+		l.add(q.Pos, types.ErrorCodeMalformedQuery, err.Error())
+	}
+	l.query(q, l.object(q.From))
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		return l.issues[i].Pos.Offset < l.issues[j].Pos.Offset
+	})
+	return l.issues
+}
+
+type usage int
+
+const (
+	useSelect usage = iota
+	useFilter
+	useSort
+	useGroup
+	useAggregate
+)
+
+var aggregateNames = map[string]bool{
+	"COUNT": true, "COUNT_DISTINCT": true, "SUM": true, "AVG": true, "MIN": true, "MAX": true,
+}
+
+type linter struct {
+	schema Schema
+	issues []Issue
+}
+
+type scope struct {
+	meta    *sobjects.Metadata
+	alias   string
+	aliases map[string]bool
+}
+
+func (l *linter) add(pos Pos, code types.ErrorCode, format string, args ...interface{}) {
+	l.issues = append(l.issues, Issue{Pos: pos, Severity: SeverityError, Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) object(from From) *sobjects.Metadata {
+	meta := l.schema.Object(from.Name)
+	if meta == nil {
+		l.add(from.Pos, types.ErrorCodeInvalidType, "sObject type '%s' is not supported", from.Name)
+	}
+	return meta
+}
+
+func (l *linter) query(q *Query, meta *sobjects.Metadata) {
+	if meta == nil {
+		return
+	}
+	sc := &scope{meta: meta, alias: q.From.Alias, aliases: map[string]bool{}}
+	for _, item := range q.Select {
+		if item.Alias != "" {
+			sc.aliases[strings.ToLower(item.Alias)] = true
+		}
+	}
+	for _, item := range q.Select {
+		switch v := item.Expr.(type) {
+		case *Query:
+			l.childQuery(v, meta)
+		case *TypeOfExpr:
+			l.typeOf(sc, v)
+		default:
+			l.node(sc, v, useSelect)
+		}
+	}
+	l.expr(sc, q.Where, useFilter)
+	for _, g := range q.GroupBy {
+		l.node(sc, g, useGroup)
+	}
+	l.expr(sc, q.Having, useSelect)
+	for _, o := range q.OrderBy {
+		l.node(sc, o.Expr, useSort)
+	}
+}
+
+func (l *linter) childQuery(q *Query, parent *sobjects.Metadata) {
+	for _, rel := range parent.ChildRelationships {
+		if strings.EqualFold(rel.RelationshipName, q.From.Name) {
+			if child := l.schema.Object(rel.ChildSObject); child != nil {
+				l.query(q, child)
+			}
+			return
+		}
+	}
+	l.add(q.From.Pos, types.ErrorCodeInvalidType, "Didn't understand relationship '%s' in FROM part of query call", q.From.Name)
+}
+
+func (l *linter) typeOf(sc *scope, t *TypeOfExpr) {
+	var ref *sobjects.FieldMetadata
+	for i := range sc.meta.Fields {
+		if strings.EqualFold(sc.meta.Fields[i].RelationshipName, t.Field.Name) {
+			ref = &sc.meta.Fields[i]
+			break
+		}
+	}
+	if ref == nil {
+		l.add(t.Field.Pos, types.ErrorCodeInvalidField, "Didn't understand relationship '%s' in field path", t.Field.Name)
+		return
+	}
+	if len(ref.ReferenceTo) < 2 {
+		l.add(t.Field.Pos, types.ErrorCodeInvalidField, "TYPEOF requires a polymorphic relationship, '%s' is not", t.Field.Name)
+	}
+	for _, w := range t.Whens {
+		if !containsFold(ref.ReferenceTo, w.ObjectType) {
+			l.add(w.Pos, types.ErrorCodeInvalidType, "'%s' is not a valid type for relationship '%s'", w.ObjectType, t.Field.Name)
+			continue
+		}
+		if meta := l.schema.Object(w.ObjectType); meta != nil {
+			whenScope := &scope{meta: meta}
+			for _, f := range w.Fields {
+				l.field(whenScope, f, useSelect)
+			}
+		}
+	}
+}
+
+func (l *linter) expr(sc *scope, e Expr, use usage) {
+	switch v := e.(type) {
+	case *LogicalExpr:
+		for _, o := range v.Operands {
+			l.expr(sc, o, use)
+		}
+	case *NotExpr:
+		l.expr(sc, v.X, use)
+	case *ComparisonExpr:
+		l.node(sc, v.Left, use)
+		if sub, ok := v.Right.(*Query); ok {
+			l.query(sub, l.object(sub.From))
+		}
+	}
+}
+
+func (l *linter) node(sc *scope, n Node, use usage) {
+	switch v := n.(type) {
+	case *Field:
+		if use == useSort && sc.aliases[strings.ToLower(v.Name)] {
+			return
+		}
+		l.field(sc, v, use)
+	case *Func:
+		name := strings.ToUpper(v.Name)
+		if name == "FIELDS" {
+			return
+		}
+		argUse := use
+		if aggregateNames[name] {
+			argUse = useAggregate
+			if name == "COUNT" {
+				argUse = useSelect
+			}
+		}
+		for _, arg := range v.Args {
+			if f, ok := arg.(*Field); ok && argUse == useAggregate {
+				if meta := l.field(sc, f, useSelect); meta != nil && !meta.Aggregatable {
+					l.add(f.Pos, types.ErrorCodeInvalidField, "field '%s' does not support aggregate operator '%s'", f.Name, name)
+				}
+				continue
+			}
+			l.node(sc, arg, argUse)
+		}
+	}
+}
+
+// field resolves a field path and checks that it supports the usage.
+// It returns nil when the field is unknown or cannot be checked.
+func (l *linter) field(sc *scope, f *Field, use usage) *sobjects.FieldMetadata {
+	parts := strings.Split(f.Name, ".")
+	meta := sc.meta
+	if len(parts) > 1 && (strings.EqualFold(parts[0], sc.alias) || strings.EqualFold(parts[0], meta.Name)) && findRelationship(meta, parts[0]) == nil {
+		parts = parts[1:]
+	}
+	for _, rel := range parts[:len(parts)-1] {
+		ref := findRelationship(meta, rel)
+		if ref == nil {
+			l.add(f.Pos, types.ErrorCodeInvalidField, "Didn't understand relationship '%s' in field path '%s' on entity '%s'", rel, f.Name, meta.Name)
+			return nil
+		}
+		if len(ref.ReferenceTo) != 1 {
+			return nil
+		}
+		if meta = l.schema.Object(ref.ReferenceTo[0]); meta == nil {
+			return nil
+		}
+	}
+	name := parts[len(parts)-1]
+	field := findField(meta, name)
+	if field == nil {
+		l.add(f.Pos, types.ErrorCodeInvalidField, "No such column '%s' on entity '%s'", name, meta.Name)
+		return nil
+	}
+	switch {
+	case use == useFilter && !field.Filterable:
+		l.add(f.Pos, types.ErrorCodeInvalidField, "field '%s' can not be filtered in a query call", f.Name)
+	case use == useSort && !field.Sortable:
+		l.add(f.Pos, types.ErrorCodeInvalidField, "field '%s' can not be sorted in a query call", f.Name)
+	case use == useGroup && !field.Groupable:
+		l.add(f.Pos, types.ErrorCodeInvalidField, "field '%s' can not be grouped in a query call", f.Name)
+	}
+	return field
+}
+
+func findField(meta *sobjects.Metadata, name string) *sobjects.FieldMetadata {
+	for i := range meta.Fields {
+		if strings.EqualFold(meta.Fields[i].Name, name) {
+			return &meta.Fields[i]
+		}
+	}
+	return nil
+}
+
+func findRelationship(meta *sobjects.Metadata, name string) *sobjects.FieldMetadata {
+	for i := range meta.Fields {
+		if meta.Fields[i].RelationshipName != "" && strings.EqualFold(meta.Fields[i].RelationshipName, name) {
+			return &meta.Fields[i]
+		}
+	}
+	return nil
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,689 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParseError is a SOQL syntax error.
+type ParseError struct {
+	Pos     Pos
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Pos.Line, e.Pos.Column, e.Message)
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokDate
+	tokDateTime
+	tokBind
+	tokOperator
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  Pos
+}
+
+var (
+	dateTimePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})`)
+	datePattern     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}`)
+	numberPattern   = regexp.MustCompile(`^[+-]?\d+(\.\d+)?`)
+)
+
+// reserved lists the keywords that end a select item or clause, so they
+// are never taken as aliases.
+var reserved = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "WITH": true, "GROUP": true,
+	"HAVING": true, "ORDER": true, "LIMIT": true, "OFFSET": true, "FOR": true,
+	"USING": true, "AND": true, "OR": true, "NOT": true, "ASC": true,
+	"DESC": true, "NULLS": true, "UPDATE": true, "WHEN": true, "THEN": true,
+	"ELSE": true, "END": true, "LIKE": true, "IN": true, "INCLUDES": true,
+	"EXCLUDES": true,
+}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	line, col := 1, 1
+	i := 0
+	advance := func(n int) {
+		for k := 0; k < n; k++ {
+			if src[i] == '\n' {
+				line++
+				col = 1
+			} else {
+				col++
+			}
+			i++
+		}
+	}
+	for i < len(src) {
+		c := src[i]
+		pos := Pos{Offset: i, Line: line, Column: col}
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			advance(1)
+		case c == '(':
+			tokens = append(tokens, token{tokLParen, "(", pos})
+			advance(1)
+		case c == ')':
+			tokens = append(tokens, token{tokRParen, ")", pos})
+			advance(1)
+		case c == ',':
+			tokens = append(tokens, token{tokComma, ",", pos})
+			advance(1)
+		case c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != '\'' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				return nil, &ParseError{Pos: pos, Message: "unterminated string literal"}
+			}
+			tokens = append(tokens, token{tokString, src[i : j+1], pos})
+			advance(j + 1 - i)
+		case c == '=' || c == '<' || c == '>' || c == '!':
+			op := string(c)
+			if i+1 < len(src) && (src[i+1] == '=' || (c == '<' && src[i+1] == '>')) {
+				op += string(src[i+1])
+			}
+			if op == "!" {
+				return nil, &ParseError{Pos: pos, Message: "unexpected '!'"}
+			}
+			tokens = append(tokens, token{tokOperator, op, pos})
+			advance(len(op))
+		case c == ':':
+			j := i + 1
+			for j < len(src) && isIdentChar(src[j]) {
+				j++
+			}
+			if j == i+1 {
+				return nil, &ParseError{Pos: pos, Message: "expected bind variable name after ':'"}
+			}
+			tokens = append(tokens, token{tokBind, src[i:j], pos})
+			advance(j - i)
+		case c >= '0' && c <= '9' || c == '-' || c == '+':
+			rest := src[i:]
+			kind, text := tokNumber, ""
+			if m := dateTimePattern.FindString(rest); m != "" {
+				kind, text = tokDateTime, m
+			} else if m := datePattern.FindString(rest); m != "" {
+				kind, text = tokDate, m
+			} else if m := numberPattern.FindString(rest); m != "" {
+				text = m
+			} else {
+				return nil, &ParseError{Pos: pos, Message: fmt.Sprintf("unexpected %q", c)}
+			}
+			tokens = append(tokens, token{kind, text, pos})
+			advance(len(text))
+		case isIdentStart(c):
+			j := i
+			for j < len(src) && isIdentChar(src[j]) {
+				j++
+			}
+			// Date literals with a parameter, such as LAST_N_DAYS:30.
+			if j+1 < len(src) && src[j] == ':' && src[j+1] >= '0' && src[j+1] <= '9' {
+				j++
+				for j < len(src) && src[j] >= '0' && src[j] <= '9' {
+					j++
+				}
+			}
+			tokens = append(tokens, token{tokIdent, src[i:j], pos})
+			advance(j - i)
+		default:
+			return nil, &ParseError{Pos: pos, Message: fmt.Sprintf("unexpected %q", c)}
+		}
+	}
+	tokens = append(tokens, token{tokEOF, "", Pos{Offset: i, Line: line, Column: col}})
+	return tokens, nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9' || c == '.'
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse parses a SOQL query into an AST.
+func Parse(soql string) (*Query, error) {
+	tokens, err := tokenize(soql)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	q, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %q after end of query", t.text)
+	}
+	return q, nil
+}
+
+// MustParse is like Parse but panics on error. It is intended for queries
+// known at compile time.
+func MustParse(soql string) *Query {
+	q, err := Parse(soql)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) peekAt(n int) token {
+	if p.pos+n < len(p.tokens) {
+		return p.tokens[p.pos+n]
+	}
+	return p.tokens[len(p.tokens)-1]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return &ParseError{Pos: t.pos, Message: fmt.Sprintf(format, args...)}
+}
+
+func isKeyword(t token, keyword string) bool {
+	return t.kind == tokIdent && strings.EqualFold(t.text, keyword)
+}
+
+func (p *parser) acceptKeyword(keyword string) bool {
+	if isKeyword(p.peek(), keyword) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectKeyword(keyword string) (token, error) {
+	t := p.next()
+	if !isKeyword(t, keyword) {
+		return t, p.errorf(t, "expected %s, found %s", keyword, describe(t))
+	}
+	return t, nil
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, p.errorf(t, "expected %s, found %s", what, describe(t))
+	}
+	return t, nil
+}
+
+func describe(t token) string {
+	if t.kind == tokEOF {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+func (p *parser) parseQuery() (*Query, error) {
+	start, err := p.expectKeyword("SELECT")
+	if err != nil {
+		return nil, err
+	}
+	q := &Query{Pos: start.pos}
+	for {
+		item, err := p.parseSelectItem()
+		if err != nil {
+			return nil, err
+		}
+		q.Select = append(q.Select, item)
+		if p.peek().kind != tokComma {
+			break
+		}
+		p.next()
+	}
+	if _, err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	from, err := p.expect(tokIdent, "object name")
+	if err != nil {
+		return nil, err
+	}
+	q.From = From{Pos: from.pos, Name: from.text}
+	if t := p.peek(); t.kind == tokIdent && !reserved[strings.ToUpper(t.text)] {
+		q.From.Alias = p.next().text
+	}
+	if p.acceptKeyword("USING") {
+		if _, err := p.expectKeyword("SCOPE"); err != nil {
+			return nil, err
+		}
+		scope, err := p.expect(tokIdent, "scope")
+		if err != nil {
+			return nil, err
+		}
+		q.Scope = strings.ToLower(scope.text)
+	}
+	if p.acceptKeyword("WHERE") {
+		if q.Where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptKeyword("WITH") {
+		t, err := p.expect(tokIdent, "SECURITY_ENFORCED, USER_MODE or SYSTEM_MODE")
+		if err != nil {
+			return nil, err
+		}
+		switch mode := strings.ToUpper(t.text); mode {
+		case string(SecurityEnforced), string(UserMode), string(SystemMode):
+			q.With = mode
+		default:
+			return nil, p.errorf(t, "unsupported WITH clause %q", t.text)
+		}
+	}
+	if p.acceptKeyword("GROUP") {
+		if _, err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			g, err := p.parseValueExpr()
+			if err != nil {
+				return nil, err
+			}
+			q.GroupBy = append(q.GroupBy, g)
+			if p.peek().kind != tokComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if p.acceptKeyword("HAVING") {
+		if q.Having, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptKeyword("ORDER") {
+		if _, err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			item, err := p.parseOrderItem()
+			if err != nil {
+				return nil, err
+			}
+			q.OrderBy = append(q.OrderBy, item)
+			if p.peek().kind != tokComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if p.acceptKeyword("LIMIT") {
+		if q.Limit, err = p.parseInt(); err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptKeyword("OFFSET") {
+		if q.Offset, err = p.parseInt(); err != nil {
+			return nil, err
+		}
+	}
+	for isKeyword(p.peek(), "FOR") {
+		p.next()
+		t, err := p.expect(tokIdent, "VIEW, REFERENCE or UPDATE")
+		if err != nil {
+			return nil, err
+		}
+		switch f := strings.ToUpper(t.text); f {
+		case "VIEW", "REFERENCE", "UPDATE":
+			q.For = append(q.For, f)
+		default:
+			return nil, p.errorf(t, "unexpected FOR %s", t.text)
+		}
+	}
+	return q, nil
+}
+
+func (p *parser) parseSelectItem() (SelectItem, error) {
+	t := p.peek()
+	if t.kind == tokLParen && isKeyword(p.peekAt(1), "SELECT") {
+		p.next()
+		sub, err := p.parseQuery()
+		if err != nil {
+			return SelectItem{}, err
+		}
+		if _, err := p.expect(tokRParen, "')'"); err != nil {
+			return SelectItem{}, err
+		}
+		return SelectItem{Expr: sub}, nil
+	}
+	if isKeyword(t, "TYPEOF") {
+		typeOf, err := p.parseTypeOf()
+		return SelectItem{Expr: typeOf}, err
+	}
+	expr, err := p.parseValueExpr()
+	if err != nil {
+		return SelectItem{}, err
+	}
+	item := SelectItem{Expr: expr}
+	if a := p.peek(); a.kind == tokIdent && !reserved[strings.ToUpper(a.text)] {
+		item.Alias = p.next().text
+	}
+	return item, nil
+}
+
+func (p *parser) parseTypeOf() (*TypeOfExpr, error) {
+	start := p.next()
+	field, err := p.expect(tokIdent, "relationship name")
+	if err != nil {
+		return nil, err
+	}
+	t := &TypeOfExpr{Pos: start.pos, Field: &Field{Pos: field.pos, Name: field.text}}
+	for isKeyword(p.peek(), "WHEN") {
+		when := p.next()
+		objectType, err := p.expect(tokIdent, "object type")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expectKeyword("THEN"); err != nil {
+			return nil, err
+		}
+		fields, err := p.parseFieldList()
+		if err != nil {
+			return nil, err
+		}
+		t.Whens = append(t.Whens, TypeOfWhen{Pos: when.pos, ObjectType: objectType.text, Fields: fields})
+	}
+	if len(t.Whens) == 0 {
+		return nil, p.errorf(p.peek(), "expected WHEN, found %s", describe(p.peek()))
+	}
+	if p.acceptKeyword("ELSE") {
+		if t.Else, err = p.parseFieldList(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.expectKeyword("END"); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (p *parser) parseFieldList() ([]*Field, error) {
+	var fields []*Field
+	for {
+		t, err := p.expect(tokIdent, "field name")
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, &Field{Pos: t.pos, Name: t.text})
+		if p.peek().kind != tokComma {
+			return fields, nil
+		}
+		p.next()
+	}
+}
+
+// parseValueExpr parses a field reference or function call.
+func (p *parser) parseValueExpr() (Node, error) {
+	t, err := p.expect(tokIdent, "field name")
+	if err != nil {
+		return nil, err
+	}
+	if reserved[strings.ToUpper(t.text)] {
+		return nil, p.errorf(t, "expected field name, found %s", describe(t))
+	}
+	if p.peek().kind != tokLParen {
+		return &Field{Pos: t.pos, Name: t.text}, nil
+	}
+	p.next()
+	fn := &Func{Pos: t.pos, Name: t.text}
+	if p.peek().kind == tokRParen {
+		p.next()
+		return fn, nil
+	}
+	for {
+		var arg Node
+		switch a := p.peek(); a.kind {
+		case tokIdent:
+			if arg, err = p.parseValueExpr(); err != nil {
+				return nil, err
+			}
+		case tokString, tokNumber:
+			p.next()
+			arg = p.literal(a)
+		default:
+			return nil, p.errorf(a, "unexpected %s in function arguments", describe(a))
+		}
+		fn.Args = append(fn.Args, arg)
+		if p.peek().kind != tokComma {
+			break
+		}
+		p.next()
+	}
+	if _, err := p.expect(tokRParen, "')'"); err != nil {
+		return nil, err
+	}
+	return fn, nil
+}
+
+func (p *parser) parseOrderItem() (OrderItem, error) {
+	expr, err := p.parseValueExpr()
+	if err != nil {
+		return OrderItem{}, err
+	}
+	item := OrderItem{Expr: expr}
+	if isKeyword(p.peek(), "ASC") || isKeyword(p.peek(), "DESC") {
+		item.Direction = strings.ToUpper(p.next().text)
+	}
+	if p.acceptKeyword("NULLS") {
+		t := p.next()
+		if !isKeyword(t, "FIRST") && !isKeyword(t, "LAST") {
+			return OrderItem{}, p.errorf(t, "expected FIRST or LAST, found %s", describe(t))
+		}
+		item.Nulls = strings.ToUpper(t.text)
+	}
+	return item, nil
+}
+
+func (p *parser) parseInt() (int, error) {
+	t, err := p.expect(tokNumber, "number")
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(t.text)
+	if err != nil || n < 0 {
+		return 0, p.errorf(t, "expected a non-negative integer, found %q", t.text)
+	}
+	return n, nil
+}
+
+// parseExpr parses OR-separated terms; AND binds tighter than OR.
+func (p *parser) parseExpr() (Expr, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	if !isKeyword(p.peek(), "OR") {
+		return first, nil
+	}
+	or := &LogicalExpr{Pos: first.Position(), Op: "OR", Operands: []Expr{first}}
+	for p.acceptKeyword("OR") {
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or.Operands = append(or.Operands, next)
+	}
+	return or, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if !isKeyword(p.peek(), "AND") {
+		return first, nil
+	}
+	and := &LogicalExpr{Pos: first.Position(), Op: "AND", Operands: []Expr{first}}
+	for p.acceptKeyword("AND") {
+		next, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		and.Operands = append(and.Operands, next)
+	}
+	return and, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	t := p.peek()
+	if isKeyword(t, "NOT") {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &NotExpr{Pos: t.pos, X: x}, nil
+	}
+	if t.kind == tokLParen {
+		p.next()
+		x, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return x, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	left, err := p.parseValueExpr()
+	if err != nil {
+		return nil, err
+	}
+	cmp := &ComparisonExpr{Pos: left.Position(), Left: left}
+	t := p.next()
+	switch {
+	case t.kind == tokOperator:
+		cmp.Op = t.text
+		if cmp.Op == "<>" {
+			cmp.Op = "!="
+		}
+	case isKeyword(t, "LIKE"), isKeyword(t, "IN"), isKeyword(t, "INCLUDES"), isKeyword(t, "EXCLUDES"):
+		cmp.Op = strings.ToUpper(t.text)
+	case isKeyword(t, "NOT") && isKeyword(p.peek(), "IN"):
+		p.next()
+		cmp.Op = "NOT IN"
+	default:
+		return nil, p.errorf(t, "expected comparison operator, found %s", describe(t))
+	}
+	switch cmp.Op {
+	case "IN", "NOT IN", "INCLUDES", "EXCLUDES":
+		if p.peek().kind == tokBind {
+			cmp.Right = p.literal(p.next())
+			return cmp, nil
+		}
+		open, err := p.expect(tokLParen, "'('")
+		if err != nil {
+			return nil, err
+		}
+		if isKeyword(p.peek(), "SELECT") && (cmp.Op == "IN" || cmp.Op == "NOT IN") {
+			sub, err := p.parseQuery()
+			if err != nil {
+				return nil, err
+			}
+			cmp.Right = sub
+		} else {
+			list := &List{Pos: open.pos}
+			for {
+				v, err := p.parseLiteral()
+				if err != nil {
+					return nil, err
+				}
+				list.Values = append(list.Values, v)
+				if p.peek().kind != tokComma {
+					break
+				}
+				p.next()
+			}
+			cmp.Right = list
+		}
+		if _, err := p.expect(tokRParen, "')'"); err != nil {
+			return nil, err
+		}
+	default:
+		if cmp.Right, err = p.parseLiteral(); err != nil {
+			return nil, err
+		}
+	}
+	return cmp, nil
+}
+
+func (p *parser) parseLiteral() (*Literal, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString, tokNumber, tokDate, tokDateTime, tokBind:
+		return p.literal(t), nil
+	case tokIdent:
+		if reserved[strings.ToUpper(t.text)] {
+			break
+		}
+		return p.literal(t), nil
+	}
+	return nil, p.errorf(t, "expected value, found %s", describe(t))
+}
+
+func (p *parser) literal(t token) *Literal {
+	l := &Literal{Pos: t.pos, Text: t.text}
+	switch t.kind {
+	case tokString:
+		l.Kind = LiteralString
+	case tokNumber:
+		l.Kind = LiteralNumber
+	case tokDate:
+		l.Kind = LiteralDate
+	case tokDateTime:
+		l.Kind = LiteralDateTime
+	case tokBind:
+		l.Kind = LiteralBind
+	default:
+		switch strings.ToUpper(t.text) {
+		case "TRUE", "FALSE":
+			l.Kind = LiteralBool
+		case "NULL":
+			l.Kind = LiteralNull
+		default:
+			l.Kind = LiteralConstant
+		}
+	}
+	return l
+}
@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
+
+	"github.com/PramithaMJ/salesforce/v2/types"
 )
 
 // SObject represents a query result record.
@@ -35,6 +38,29 @@ func (s *SObject) StringField(key string) string {
 	return ""
 }
 
+// FloatField returns a numeric field as float64, or 0 if it is null.
+func (s *SObject) FloatField(key string) float64 {
+	switch v := s.Get(key).(type) {
+	case float64:
+		return v
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	}
+	return 0
+}
+
+// IntField returns a numeric field as int, or 0 if it is null.
+func (s *SObject) IntField(key string) int {
+	return int(s.FloatField(key))
+}
+
+// BoolField returns a checkbox field as bool.
+func (s *SObject) BoolField(key string) bool {
+	v, _ := s.Get(key).(bool)
+	return v
+}
+
 // ID returns the record ID.
 func (s *SObject) ID() string { return s.StringField("Id") }
 
@@ -63,8 +89,9 @@ type HTTPClient interface {
 
 // Service provides SOQL query operations.
 type Service struct {
-	client     HTTPClient
-	apiVersion string
+	client        HTTPClient
+	apiVersion    string
+	explainLogger types.Logger
 }
 
 // NewService creates a new Query service.
@@ -74,12 +101,14 @@ func NewService(client HTTPClient, apiVersion string) *Service {
 
 // Execute runs a SOQL query.
 func (s *Service) Execute(ctx context.Context, query string) (*Result, error) {
+	s.checkPlan(ctx, query)
 	path := fmt.Sprintf("/services/data/v%s/query?q=%s", s.apiVersion, url.QueryEscape(query))
 	return s.executeQuery(ctx, path)
 }
 
 // ExecuteAll runs a SOQL query including deleted/archived records.
 func (s *Service) ExecuteAll(ctx context.Context, query string) (*Result, error) {
+	s.checkPlan(ctx, query)
 	path := fmt.Sprintf("/services/data/v%s/queryAll?q=%s", s.apiVersion, url.QueryEscape(query))
 	return s.executeQuery(ctx, path)
 }
@@ -137,6 +166,10 @@ func (s *Service) executeQuery(ctx context.Context, path string) (*Result, error
 	if err != nil {
 		return nil, err
 	}
+	return parseResult(respBody)
+}
+
+func parseResult(respBody []byte) (*Result, error) {
 	var result Result
 	if err := json.Unmarshal(respBody, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
@@ -157,10 +190,13 @@ func (s *Service) NewBuilder(objectType string) *Builder {
 type Builder struct {
 	objectType string
 	fields     []string
-	conditions []string
+	subqueries []*Builder
+	scope      Scope
+	conditions []Condition
+	security   []Security
 	orderBy    []string
 	groupBy    []string
-	having     []string
+	having     []Condition
 	limit      int
 	offset     int
 	forView    bool
@@ -181,70 +217,59 @@ func (b *Builder) Select(fields ...string) *Builder {
 
 // Where adds a WHERE condition.
 func (b *Builder) Where(condition string) *Builder {
+	return b.WhereExpr(Raw(condition))
+}
+
+// WhereExpr adds a composed WHERE condition, such as Or(...) or Not(...).
+// Conditions added by separate calls are combined with AND.
+func (b *Builder) WhereExpr(condition Condition) *Builder {
 	b.conditions = append(b.conditions, condition)
 	return b
 }
 
 // WhereEquals adds an equality condition.
 func (b *Builder) WhereEquals(field string, value interface{}) *Builder {
-	b.conditions = append(b.conditions, fmt.Sprintf("%s = %s", field, formatValue(value)))
-	return b
+	return b.WhereExpr(Equals(field, value))
 }
 
 // WhereNotEquals adds a not-equal condition.
 func (b *Builder) WhereNotEquals(field string, value interface{}) *Builder {
-	b.conditions = append(b.conditions, fmt.Sprintf("%s != %s", field, formatValue(value)))
-	return b
+	return b.WhereExpr(NotEquals(field, value))
 }
 
 // WhereIn adds an IN condition.
 func (b *Builder) WhereIn(field string, values ...interface{}) *Builder {
-	formatted := make([]string, len(values))
-	for i, v := range values {
-		formatted[i] = formatValue(v)
-	}
-	b.conditions = append(b.conditions, fmt.Sprintf("%s IN (%s)", field, strings.Join(formatted, ", ")))
-	return b
+	return b.WhereExpr(In(field, values...))
 }
 
 // WhereNotIn adds a NOT IN condition.
 func (b *Builder) WhereNotIn(field string, values ...interface{}) *Builder {
-	formatted := make([]string, len(values))
-	for i, v := range values {
-		formatted[i] = formatValue(v)
-	}
-	b.conditions = append(b.conditions, fmt.Sprintf("%s NOT IN (%s)", field, strings.Join(formatted, ", ")))
-	return b
+	return b.WhereExpr(NotIn(field, values...))
 }
 
 // WhereLike adds a LIKE condition.
 func (b *Builder) WhereLike(field, pattern string) *Builder {
-	b.conditions = append(b.conditions, fmt.Sprintf("%s LIKE '%s'", field, escapeSoql(pattern)))
-	return b
+	return b.WhereExpr(Like(field, pattern))
 }
 
 // WhereNull adds an IS NULL condition.
 func (b *Builder) WhereNull(field string) *Builder {
-	b.conditions = append(b.conditions, field+" = NULL")
-	return b
+	return b.WhereExpr(IsNull(field))
 }
 
 // WhereNotNull adds an IS NOT NULL condition.
 func (b *Builder) WhereNotNull(field string) *Builder {
-	b.conditions = append(b.conditions, field+" != NULL")
-	return b
+	return b.WhereExpr(IsNotNull(field))
 }
 
 // WhereGreaterThan adds a > condition.
 func (b *Builder) WhereGreaterThan(field string, value interface{}) *Builder {
-	b.conditions = append(b.conditions, fmt.Sprintf("%s > %s", field, formatValue(value)))
-	return b
+	return b.WhereExpr(GreaterThan(field, value))
 }
 
 // WhereLessThan adds a < condition.
 func (b *Builder) WhereLessThan(field string, value interface{}) *Builder {
-	b.conditions = append(b.conditions, fmt.Sprintf("%s < %s", field, formatValue(value)))
-	return b
+	return b.WhereExpr(LessThan(field, value))
 }
 
 // OrderByAsc adds ascending ORDER BY.
@@ -279,6 +304,11 @@ func (b *Builder) GroupBy(fields ...string) *Builder {
 
 // Having adds HAVING conditions.
 func (b *Builder) Having(condition string) *Builder {
+	return b.HavingExpr(Raw(condition))
+}
+
+// HavingExpr adds a composed HAVING condition.
+func (b *Builder) HavingExpr(condition Condition) *Builder {
 	b.having = append(b.having, condition)
 	return b
 }
@@ -313,28 +343,42 @@ func (b *Builder) ForUpdate() *Builder {
 	return b
 }
 
-// Build generates the SOQL query string.
+// Build generates the SOQL query string. Use Validate to check it against
+// Salesforce clause restrictions before sending it.
 func (b *Builder) Build() string {
 	var sb strings.Builder
 	sb.WriteString("SELECT ")
-	if len(b.fields) == 0 {
-		sb.WriteString("Id")
-	} else {
-		sb.WriteString(strings.Join(b.fields, ", "))
+	fields := b.fields
+	if len(fields) == 0 {
+		fields = []string{"Id"}
+	}
+	sb.WriteString(strings.Join(fields, ", "))
+	for _, sub := range b.subqueries {
+		sb.WriteString(", (")
+		sb.WriteString(sub.Build())
+		sb.WriteString(")")
 	}
 	sb.WriteString(" FROM ")
 	sb.WriteString(b.objectType)
-	if len(b.conditions) > 0 {
+	if b.scope != "" {
+		sb.WriteString(" USING SCOPE ")
+		sb.WriteString(string(b.scope))
+	}
+	if where := And(b.conditions...).SOQL(); where != "" {
 		sb.WriteString(" WHERE ")
-		sb.WriteString(strings.Join(b.conditions, " AND "))
+		sb.WriteString(where)
+	}
+	for _, mode := range b.security {
+		sb.WriteString(" WITH ")
+		sb.WriteString(string(mode))
 	}
 	if len(b.groupBy) > 0 {
 		sb.WriteString(" GROUP BY ")
 		sb.WriteString(strings.Join(b.groupBy, ", "))
 	}
-	if len(b.having) > 0 {
+	if having := And(b.having...).SOQL(); having != "" {
 		sb.WriteString(" HAVING ")
-		sb.WriteString(strings.Join(b.having, " AND "))
+		sb.WriteString(having)
 	}
 	if len(b.orderBy) > 0 {
 		sb.WriteString(" ORDER BY ")
@@ -369,6 +413,18 @@ func formatValue(v interface{}) string {
 		return "FALSE"
 	case nil:
 		return "NULL"
+	case DateLiteral:
+		return string(val)
+	case types.ID:
+		return fmt.Sprintf("'%s'", escapeSoql(string(val)))
+	case time.Time:
+		return types.NewDateTime(val).SOQL()
+	case types.DateTime:
+		return val.SOQL()
+	case types.Date:
+		return val.SOQL()
+	case types.Time:
+		return val.SOQL()
 	default:
 		return fmt.Sprintf("%v", val)
 	}
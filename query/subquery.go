@@ -0,0 +1,147 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxSemiJoins is the largest number of semi-joins and anti-joins allowed
+// in a single query.
+const MaxSemiJoins = 2
+
+// semiJoin is a field IN (SELECT ...) or field NOT IN (SELECT ...) condition.
+type semiJoin struct {
+	field    string
+	operator string
+	sub      *Builder
+}
+
+func (j semiJoin) SOQL() string {
+	return j.field + " " + j.operator + " (" + j.sub.Build() + ")"
+}
+
+// InQuery returns a semi-join: field IN (SELECT ...).
+func InQuery(field string, sub *Builder) Condition {
+	return semiJoin{field: field, operator: "IN", sub: sub}
+}
+
+// NotInQuery returns an anti-join: field NOT IN (SELECT ...).
+func NotInQuery(field string, sub *Builder) Condition {
+	return semiJoin{field: field, operator: "NOT IN", sub: sub}
+}
+
+// SelectSubquery adds a parent-to-child relationship query. The
+// subquery's object type is the child relationship name, such as Contacts.
+func (b *Builder) SelectSubquery(sub *Builder) *Builder {
+	b.subqueries = append(b.subqueries, sub)
+	return b
+}
+
+// Validate checks the query against Salesforce's clause restrictions:
+// semi-joins and anti-joins select exactly one field, cannot appear under OR,
+// are limited to MaxSemiJoins per query, subqueries cannot be nested beyond
+// one level, and FOR UPDATE, WITH, FIELDS() and OFFSET are used in
+// combinations Salesforce accepts.
+func (b *Builder) Validate() error {
+	if err := b.validateClauses(); err != nil {
+		return err
+	}
+	var joins []semiJoin
+	for _, c := range b.conditions {
+		found, err := collectSemiJoins(c, false)
+		if err != nil {
+			return err
+		}
+		joins = append(joins, found...)
+	}
+	if len(joins) > MaxSemiJoins {
+		return fmt.Errorf("query has %d semi-joins or anti-joins, at most %d are allowed", len(joins), MaxSemiJoins)
+	}
+	for _, j := range joins {
+		if err := j.validate(); err != nil {
+			return err
+		}
+	}
+	for _, sub := range b.subqueries {
+		if sub == nil {
+			return errors.New("subquery is nil")
+		}
+		if sub.hasSubqueries() {
+			return fmt.Errorf("subquery on %s cannot contain another subquery", sub.objectType)
+		}
+	}
+	for _, c := range b.having {
+		found, err := collectSemiJoins(c, false)
+		if err != nil {
+			return err
+		}
+		if len(found) > 0 {
+			return errors.New("semi-joins and anti-joins are not allowed in HAVING")
+		}
+	}
+	return nil
+}
+
+func (j semiJoin) validate() error {
+	if j.sub == nil {
+		return fmt.Errorf("%s %s subquery is nil", j.field, j.operator)
+	}
+	if len(j.sub.fields) != 1 || len(j.sub.subqueries) > 0 {
+		return fmt.Errorf("%s %s subquery must select exactly one field", j.field, j.operator)
+	}
+	if j.sub.hasSubqueries() {
+		return fmt.Errorf("%s %s subquery cannot contain another subquery", j.field, j.operator)
+	}
+	if len(j.sub.orderBy) > 0 || j.sub.limit > 0 {
+		return fmt.Errorf("%s %s subquery cannot use ORDER BY or LIMIT", j.field, j.operator)
+	}
+	return nil
+}
+
+// hasSubqueries reports whether the query contains child subqueries or
+// semi-joins.
+func (b *Builder) hasSubqueries() bool {
+	if len(b.subqueries) > 0 {
+		return true
+	}
+	for _, c := range append(append([]Condition{}, b.conditions...), b.having...) {
+		if found, _ := collectSemiJoins(c, false); len(found) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// collectSemiJoins returns the semi-joins within a condition, failing if
+// one appears under OR.
+func collectSemiJoins(c Condition, underOr bool) ([]semiJoin, error) {
+	switch v := c.(type) {
+	case semiJoin:
+		if underOr {
+			return nil, fmt.Errorf("%s %s subquery cannot be used in an OR condition", v.field, v.operator)
+		}
+		return []semiJoin{v}, nil
+	case junction:
+		var joins []semiJoin
+		childUnderOr := underOr || (v.operator == "OR" && countNonEmpty(v.conditions) > 1)
+		for _, child := range v.conditions {
+			found, err := collectSemiJoins(child, childUnderOr)
+			if err != nil {
+				return nil, err
+			}
+			joins = append(joins, found...)
+		}
+		return joins, nil
+	case negation:
+		return collectSemiJoins(v.condition, underOr)
+	}
+	return nil, nil
+}
+
+func singleBuilder(values []interface{}) (*Builder, bool) {
+	if len(values) != 1 {
+		return nil, false
+	}
+	sub, ok := values[0].(*Builder)
+	return sub, ok && sub != nil
+}
@@ -0,0 +1,294 @@
+// Package recordsync provides incremental change synchronization built on
+// the getUpdated and getDeleted replication resources.
+package recordsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/PramithaMJ/salesforce/v2/composite"
+	"github.com/PramithaMJ/salesforce/v2/sobjects"
+	"github.com/PramithaMJ/salesforce/v2/types"
+)
+
+const (
+	// MaxWindow is the largest time range Salesforce accepts for getUpdated and getDeleted.
+	MaxWindow = 30 * 24 * time.Hour
+	// DefaultWindow is the time range requested per call when none is configured.
+	DefaultWindow = 24 * time.Hour
+	// DefaultBatchSize is the number of records fetched per collection request.
+	DefaultBatchSize = 200
+	// MaxBatchSize is the largest number of IDs a collection request accepts.
+	MaxBatchSize = composite.MaxRetrieveIDs
+	// minWindow bounds how far a window is split when it exceeds the ID limit.
+	minWindow = time.Minute
+)
+
+// EventType identifies the kind of change.
+type EventType string
+
+const (
+	EventUpsert EventType = "upsert"
+	EventDelete EventType = "delete"
+)
+
+// Event describes a single record change.
+type Event struct {
+	Type        EventType
+	ObjectType  string
+	ID          string
+	Record      *sobjects.SObject
+	DeletedDate time.Time
+}
+
+// Checkpoint records how far a sync has progressed.
+type Checkpoint struct {
+	ObjectType        string    `json:"objectType"`
+	LatestDateCovered time.Time `json:"latestDateCovered"`
+}
+
+// CheckpointStore persists checkpoints between runs.
+type CheckpointStore interface {
+	// Load returns the saved checkpoint, or nil if none exists.
+	Load(ctx context.Context, objectType string) (*Checkpoint, error)
+	Save(ctx context.Context, cp Checkpoint) error
+}
+
+// MemoryStore keeps checkpoints in memory.
+type MemoryStore struct {
+	checkpoints map[string]Checkpoint
+}
+
+// NewMemoryStore creates an in-memory checkpoint store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{checkpoints: make(map[string]Checkpoint)}
+}
+
+// Load returns the checkpoint for an object type.
+func (m *MemoryStore) Load(ctx context.Context, objectType string) (*Checkpoint, error) {
+	cp, ok := m.checkpoints[objectType]
+	if !ok {
+		return nil, nil
+	}
+	return &cp, nil
+}
+
+// Save stores a checkpoint.
+func (m *MemoryStore) Save(ctx context.Context, cp Checkpoint) error {
+	m.checkpoints[cp.ObjectType] = cp
+	return nil
+}
+
+// FileStore keeps one JSON checkpoint file per object type in a directory.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a checkpoint store rooted at dir.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// Load reads the checkpoint file for an object type.
+func (f *FileStore) Load(ctx context.Context, objectType string) (*Checkpoint, error) {
+	data, err := os.ReadFile(f.path(objectType))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	return &cp, nil
+}
+
+// Save writes the checkpoint file atomically.
+func (f *FileStore) Save(ctx context.Context, cp Checkpoint) error {
+	if err := os.MkdirAll(f.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+	tmp := f.path(cp.ObjectType) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return os.Rename(tmp, f.path(cp.ObjectType))
+}
+
+func (f *FileStore) path(objectType string) string {
+	return filepath.Join(f.dir, objectType+".json")
+}
+
+// Config configures a Syncer.
+type Config struct {
+	// ObjectType is the SObject type to synchronize.
+	ObjectType string
+	// Fields are fetched for updated records. All fields are used when empty.
+	Fields []string
+	// Start is where the sync begins when the store has no checkpoint.
+	Start time.Time
+	// Window is the time range requested per call, capped at MaxWindow.
+	Window time.Duration
+	// BatchSize is the number of records fetched per collection request.
+	BatchSize int
+	// Store persists checkpoints. An in-memory store is used when nil.
+	Store CheckpointStore
+	// Logger receives progress messages.
+	Logger types.Logger
+}
+
+// Syncer walks getUpdated/getDeleted windows and emits change events.
+type Syncer struct {
+	sobjects  *sobjects.Service
+	composite *composite.Service
+	cfg       Config
+}
+
+// New creates a Syncer.
+func New(sobjectsSvc *sobjects.Service, compositeSvc *composite.Service, cfg Config) *Syncer {
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultWindow
+	}
+	if cfg.Window > MaxWindow {
+		cfg.Window = MaxWindow
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.BatchSize > MaxBatchSize {
+		cfg.BatchSize = MaxBatchSize
+	}
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryStore()
+	}
+	return &Syncer{sobjects: sobjectsSvc, composite: compositeSvc, cfg: cfg}
+}
+
+// Run emits changes from the saved checkpoint (or Config.Start) up to end,
+// saving a checkpoint after each window. A nil checkpoint is returned only
+// on error. If fn returns an error the sync stops at the last saved checkpoint.
+func (s *Syncer) Run(ctx context.Context, end time.Time, fn func(Event) error) (*Checkpoint, error) {
+	if s.cfg.ObjectType == "" {
+		return nil, errors.New("recordsync: object type is required")
+	}
+	cp, err := s.cfg.Store.Load(ctx, s.cfg.ObjectType)
+	if err != nil {
+		return nil, err
+	}
+	if cp == nil {
+		cp = &Checkpoint{ObjectType: s.cfg.ObjectType, LatestDateCovered: s.cfg.Start}
+	}
+	if cp.LatestDateCovered.IsZero() {
+		return nil, errors.New("recordsync: no checkpoint found and no start time configured")
+	}
+	fields, err := s.fields(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	window := s.cfg.Window
+	for cp.LatestDateCovered.Before(end) {
+		if err := ctx.Err(); err != nil {
+			return cp, err
+		}
+		windowEnd := cp.LatestDateCovered.Add(window)
+		if windowEnd.After(end) {
+			windowEnd = end
+		}
+		covered, err := s.syncWindow(ctx, cp.LatestDateCovered, windowEnd, fields, fn)
+		if types.HasErrorCode(err, types.ErrorCodeExceededIDLimit) && window > minWindow {
+			window /= 2
+			s.logDebug("window exceeded ID limit, splitting", "window", window)
+			continue
+		}
+		if err != nil {
+			return cp, err
+		}
+		if !covered.After(cp.LatestDateCovered) {
+			// Salesforce has not covered any further time yet.
+			break
+		}
+		cp.LatestDateCovered = covered
+		if err := s.cfg.Store.Save(ctx, *cp); err != nil {
+			return cp, err
+		}
+		s.logDebug("sync checkpoint saved", "object", s.cfg.ObjectType, "latestDateCovered", covered)
+		window = s.cfg.Window
+	}
+	return cp, nil
+}
+
+func (s *Syncer) syncWindow(ctx context.Context, start, end time.Time, fields []string, fn func(Event) error) (time.Time, error) {
+	updated, err := s.sobjects.GetUpdated(ctx, s.cfg.ObjectType, start, end)
+	if err != nil {
+		return time.Time{}, err
+	}
+	deleted, err := s.sobjects.GetDeleted(ctx, s.cfg.ObjectType, start, end)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for i := 0; i < len(updated.IDs); i += s.cfg.BatchSize {
+		batch := updated.IDs[i:min(i+s.cfg.BatchSize, len(updated.IDs))]
+		records, err := s.composite.RetrieveCollection(ctx, s.cfg.ObjectType, batch, fields)
+		if err != nil {
+			return time.Time{}, err
+		}
+		for _, r := range records {
+			// Records deleted since the window closed come back as null.
+			if r == nil {
+				continue
+			}
+			record := sobjects.FromMap(r)
+			if err := fn(Event{Type: EventUpsert, ObjectType: s.cfg.ObjectType, ID: record.ID(), Record: record}); err != nil {
+				return time.Time{}, err
+			}
+		}
+	}
+	for _, d := range deleted.DeletedRecords {
+		deletedDate, _ := types.ParseDateTime(d.DeletedDate)
+		if err := fn(Event{Type: EventDelete, ObjectType: s.cfg.ObjectType, ID: d.ID, DeletedDate: deletedDate.Time}); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	covered, err := types.ParseDateTime(updated.LatestDateCovered)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse latestDateCovered: %w", err)
+	}
+	if deletedCovered, err := types.ParseDateTime(deleted.LatestDateCovered); err == nil && deletedCovered.Before(covered.Time) {
+		covered = deletedCovered
+	}
+	return covered.Time, nil
+}
+
+func (s *Syncer) fields(ctx context.Context) ([]string, error) {
+	if len(s.cfg.Fields) > 0 {
+		return s.cfg.Fields, nil
+	}
+	meta, err := s.sobjects.Describe(ctx, s.cfg.ObjectType)
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]string, len(meta.Fields))
+	for i, f := range meta.Fields {
+		fields[i] = f.Name
+	}
+	return fields, nil
+}
+
+func (s *Syncer) logDebug(msg string, args ...interface{}) {
+	if s.cfg.Logger != nil {
+		s.cfg.Logger.Debug(msg, args...)
+	}
+}
@@ -0,0 +1,324 @@
+// Package router runs SOQL queries through the REST query API or a Bulk
+// API 2.0 query job, depending on how many records they return.
+package router
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PramithaMJ/salesforce/v2/bulk"
+	"github.com/PramithaMJ/salesforce/v2/query"
+	"github.com/PramithaMJ/salesforce/v2/types"
+)
+
+const (
+	// DefaultThreshold is the record count above which queries move to Bulk API.
+	DefaultThreshold = 50000
+	// DefaultPageSize is the number of records read per bulk results request.
+	DefaultPageSize = 50000
+	// DefaultPollInterval is how often a bulk query job's state is checked.
+	DefaultPollInterval = 5 * time.Second
+)
+
+// Strategy decides how the record count is determined.
+type Strategy int
+
+const (
+	// StrategyFirstPage runs the query through REST and uses the first
+	// page's totalSize. Small results need no extra request.
+	StrategyFirstPage Strategy = iota
+	// StrategyCount runs SELECT COUNT() first, avoiding a full first page
+	// for queries that will go to Bulk API.
+	StrategyCount
+)
+
+// Source identifies the API that served a query.
+type Source string
+
+const (
+	SourceREST Source = "rest"
+	SourceBulk Source = "bulk"
+)
+
+// Config configures a Router.
+type Config struct {
+	// Threshold is the record count above which Bulk API is used.
+	Threshold int
+	// Strategy decides how the record count is determined.
+	Strategy Strategy
+	// PageSize is the maxRecords of each bulk results request.
+	PageSize int
+	// PollInterval is how often a bulk query job's state is checked.
+	PollInterval time.Duration
+	// IncludeDeleted includes deleted and archived records.
+	IncludeDeleted bool
+	// Logger receives routing decisions.
+	Logger types.Logger
+}
+
+// Router runs queries through REST or Bulk API.
+type Router struct {
+	query *query.Service
+	bulk  *bulk.Service
+	cfg   Config
+}
+
+// New creates a Router.
+func New(querySvc *query.Service, bulkSvc *bulk.Service, cfg Config) *Router {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = DefaultThreshold
+	}
+	if cfg.PageSize <= 0 {
+		cfg.PageSize = DefaultPageSize
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	return &Router{query: querySvc, bulk: bulkSvc, cfg: cfg}
+}
+
+// Query runs a query and returns an iterator over its records. Queries that
+// Bulk API cannot run, such as aggregates or parent-to-child subqueries,
+// always use REST. Bulk records hold string values, with relationship
+// columns such as Account.Name nested as in REST results.
+func (r *Router) Query(ctx context.Context, soql string) (*Iterator, error) {
+	parsed, err := query.Parse(soql)
+	if err != nil {
+		return nil, err
+	}
+	if reason := bulkUnsupported(parsed); reason != "" {
+		r.logDebug("Routing query to REST", "reason", reason)
+		return r.restIterator(ctx, soql, nil)
+	}
+
+	var first *query.Result
+	var count int
+	switch r.cfg.Strategy {
+	case StrategyCount:
+		countQuery := *parsed
+		countQuery.Select = []query.SelectItem{{Expr: &query.Func{Name: "COUNT"}}}
+		countQuery.OrderBy = nil
+		result, err := r.execute(ctx, countQuery.String())
+		if err != nil {
+			return nil, err
+		}
+		count = result.TotalSize
+	default:
+		if first, err = r.execute(ctx, soql); err != nil {
+			return nil, err
+		}
+		count = first.TotalSize
+	}
+
+	if count <= r.cfg.Threshold {
+		r.logDebug("Routing query to REST", "records", count)
+		return r.restIterator(ctx, soql, first)
+	}
+	r.logDebug("Routing query to Bulk API", "records", count)
+	return r.bulkIterator(ctx, soql, count)
+}
+
+func (r *Router) execute(ctx context.Context, soql string) (*query.Result, error) {
+	if r.cfg.IncludeDeleted {
+		return r.query.ExecuteAll(ctx, soql)
+	}
+	return r.query.Execute(ctx, soql)
+}
+
+func (r *Router) restIterator(ctx context.Context, soql string, first *query.Result) (*Iterator, error) {
+	if first == nil {
+		var err error
+		if first, err = r.execute(ctx, soql); err != nil {
+			return nil, err
+		}
+	}
+	next := first.NextRecordsURL
+	it := &Iterator{
+		ctx:       ctx,
+		source:    SourceREST,
+		totalSize: first.TotalSize,
+		records:   first.Records,
+		more:      first.HasMore(),
+	}
+	it.fetch = func(ctx context.Context) ([]*query.SObject, bool, error) {
+		result, err := r.query.QueryMore(ctx, next)
+		if err != nil {
+			return nil, false, err
+		}
+		next = result.NextRecordsURL
+		return result.Records, result.HasMore(), nil
+	}
+	return it, nil
+}
+
+func (r *Router) bulkIterator(ctx context.Context, soql string, count int) (*Iterator, error) {
+	req := bulk.QueryJobRequest{Query: soql}
+	if r.cfg.IncludeDeleted {
+		req.Operation = "queryAll"
+	}
+	job, err := r.bulk.CreateQueryJob(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	job, err = r.bulk.WaitForQueryCompletion(ctx, job.ID, r.cfg.PollInterval)
+	if err != nil {
+		return nil, err
+	}
+	if job.State != bulk.StateJobComplete {
+		return nil, fmt.Errorf("bulk query job %s ended in state %s: %s", job.ID, job.State, job.ErrorMessage)
+	}
+	jobID, locator := job.ID, ""
+	it := &Iterator{
+		ctx:       ctx,
+		source:    SourceBulk,
+		jobID:     jobID,
+		totalSize: count,
+		more:      true,
+	}
+	if job.NumberRecordsProcessed > 0 {
+		it.totalSize = job.NumberRecordsProcessed
+	}
+	it.fetch = func(ctx context.Context) ([]*query.SObject, bool, error) {
+		rows, nextLocator, err := r.bulk.GetQueryResults(ctx, jobID, r.cfg.PageSize, locator)
+		if err != nil {
+			return nil, false, err
+		}
+		locator = nextLocator
+		records := make([]*query.SObject, len(rows))
+		for i, row := range rows {
+			records[i] = query.FromMap(nest(row))
+		}
+		return records, locator != "" && locator != "null", nil
+	}
+	return it, nil
+}
+
+func (r *Router) logDebug(msg string, args ...interface{}) {
+	if r.cfg.Logger != nil {
+		r.cfg.Logger.Debug(msg, args...)
+	}
+}
+
+// bulkUnsupported returns why Bulk API cannot run a query, or "" if it can.
+func bulkUnsupported(q *query.Query) string {
+	switch {
+	case len(q.GroupBy) > 0:
+		return "GROUP BY"
+	case q.Offset > 0:
+		return "OFFSET"
+	case len(q.For) > 0:
+		return "FOR clause"
+	}
+	for _, item := range q.Select {
+		switch v := item.Expr.(type) {
+		case *query.Query:
+			return "parent-to-child subquery"
+		case *query.TypeOfExpr:
+			return "TYPEOF"
+		case *query.Func:
+			name := strings.ToUpper(v.Name)
+			if name != "TOLABEL" && name != "CONVERTCURRENCY" && name != "FORMAT" {
+				return name + "()"
+			}
+		}
+	}
+	return ""
+}
+
+// nest turns flat bulk columns such as Account.Owner.Name into nested maps,
+// matching the shape of REST records. Empty values become nil.
+func nest(row map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(row))
+	for key, value := range row {
+		if s, ok := value.(string); ok && s == "" {
+			value = nil
+		}
+		parts := strings.Split(key, ".")
+		m := out
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := m[part].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				m[part] = child
+			}
+			m = child
+		}
+		m[parts[len(parts)-1]] = value
+	}
+	return out
+}
+
+// Iterator walks the records of a routed query.
+type Iterator struct {
+	ctx       context.Context
+	source    Source
+	jobID     string
+	totalSize int
+	fetch     func(ctx context.Context) ([]*query.SObject, bool, error)
+	records   []*query.SObject
+	index     int
+	record    *query.SObject
+	more      bool
+	err       error
+	closed    bool
+}
+
+// Next advances to the next record. It returns false when the results are
+// exhausted, an error occurs or the iterator is closed.
+func (it *Iterator) Next() bool {
+	for !it.closed {
+		if it.index < len(it.records) {
+			it.record = it.records[it.index]
+			it.index++
+			return true
+		}
+		if !it.more {
+			break
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			break
+		}
+		records, more, err := it.fetch(it.ctx)
+		if err != nil {
+			it.err = err
+			break
+		}
+		it.records, it.index, it.more = records, 0, more
+	}
+	it.record = nil
+	return false
+}
+
+// Record returns the current record.
+func (it *Iterator) Record() *query.SObject {
+	return it.record
+}
+
+// Err returns the error that stopped iteration, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Source returns the API serving the records.
+func (it *Iterator) Source() Source {
+	return it.source
+}
+
+// JobID returns the bulk query job ID, or "" for REST queries.
+func (it *Iterator) JobID() string {
+	return it.jobID
+}
+
+// TotalSize returns the number of records the query matched.
+func (it *Iterator) TotalSize() int {
+	return it.totalSize
+}
+
+// Close stops iteration.
+func (it *Iterator) Close() {
+	it.closed = true
+	it.records = nil
+}
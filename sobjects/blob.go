@@ -0,0 +1,264 @@
+package sobjects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	sfhttp "github.com/PramithaMJ/salesforce/v2/http"
+)
+
+// StreamingHTTPClient is implemented by HTTP clients that can stream request
+// and response bodies. The SDK's http.Client satisfies it.
+type StreamingHTTPClient interface {
+	Do(ctx context.Context, req sfhttp.Request) (*sfhttp.Response, error)
+}
+
+// ErrStreamingUnsupported is returned when the service's HTTP client cannot stream.
+var ErrStreamingUnsupported = errors.New("sobjects: HTTP client does not support streaming requests")
+
+// ShareType controls the permission granted by a ContentDocumentLink.
+type ShareType string
+
+const (
+	ShareTypeViewer       ShareType = "V"
+	ShareTypeCollaborator ShareType = "C"
+	ShareTypeInferred     ShareType = "I"
+)
+
+// Visibility controls who can see a linked file.
+type Visibility string
+
+const (
+	VisibilityAllUsers      Visibility = "AllUsers"
+	VisibilityInternalUsers Visibility = "InternalUsers"
+	VisibilitySharedUsers   Visibility = "SharedUsers"
+)
+
+// Blob describes binary content uploaded to a blob field.
+type Blob struct {
+	FileName    string
+	ContentType string
+	Content     io.Reader
+}
+
+// BlobField returns the name of the blob field for an object type.
+func BlobField(objectType string) string {
+	switch strings.ToLower(objectType) {
+	case "contentversion":
+		return "VersionData"
+	case "attachment", "document":
+		return "Body"
+	}
+	return ""
+}
+
+// CreateWithBlob creates a record with a blob field using a multipart request.
+// The blob is streamed from blob.Content and never buffered in memory. The
+// request is not bounded by the HTTP client's Timeout, so large files are
+// not cut off; use ctx to bound it.
+func (s *Service) CreateWithBlob(ctx context.Context, objectType string, fields map[string]interface{}, blob Blob) (*CreateResult, error) {
+	path := fmt.Sprintf("/services/data/v%s/sobjects/%s", s.apiVersion, objectType)
+	respBody, err := s.sendBlob(ctx, http.MethodPost, path, objectType, fields, blob)
+	if err != nil {
+		return nil, err
+	}
+	var result CreateResult
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &result, nil
+}
+
+// UpdateWithBlob replaces the blob field of an existing Attachment or
+// Document. Other objects, such as ContentVersion, cannot have their blob
+// replaced; upload a new version instead.
+func (s *Service) UpdateWithBlob(ctx context.Context, objectType, id string, fields map[string]interface{}, blob Blob) error {
+	switch strings.ToLower(objectType) {
+	case "attachment", "document":
+	default:
+		return fmt.Errorf("blob updates are only supported for Attachment and Document, not %s", objectType)
+	}
+	path := fmt.Sprintf("/services/data/v%s/sobjects/%s/%s", s.apiVersion, objectType, id)
+	_, err := s.sendBlob(ctx, http.MethodPatch, path, objectType, fields, blob)
+	return err
+}
+
+// DownloadBlob streams the blob field of a record to w and returns the bytes
+// written. Like CreateWithBlob, the download is bounded by ctx rather than
+// the HTTP client's Timeout.
+func (s *Service) DownloadBlob(ctx context.Context, objectType, id, field string, w io.Writer) (int64, error) {
+	sc, ok := s.client.(StreamingHTTPClient)
+	if !ok {
+		return 0, ErrStreamingUnsupported
+	}
+	if field == "" {
+		field = BlobField(objectType)
+	}
+	if field == "" {
+		return 0, fmt.Errorf("no blob field known for %s", objectType)
+	}
+	resp, err := sc.Do(ctx, sfhttp.Request{
+		Method:    http.MethodGet,
+		Path:      fmt.Sprintf("/services/data/v%s/sobjects/%s/%s/%s", s.apiVersion, objectType, id, field),
+		Accept:    "*/*",
+		NoTimeout: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("failed to download blob: %w", err)
+	}
+	return n, nil
+}
+
+// UploadFile uploads a file as a new ContentVersion. Title and PathOnClient
+// default to the file name unless set in fields.
+func (s *Service) UploadFile(ctx context.Context, fileName string, content io.Reader, fields map[string]interface{}) (*CreateResult, error) {
+	entity := make(map[string]interface{}, len(fields)+2)
+	for k, v := range fields {
+		entity[k] = v
+	}
+	if _, ok := entity["Title"]; !ok {
+		entity["Title"] = fileName
+	}
+	if _, ok := entity["PathOnClient"]; !ok {
+		entity["PathOnClient"] = fileName
+	}
+	return s.CreateWithBlob(ctx, "ContentVersion", entity, Blob{FileName: fileName, Content: content})
+}
+
+// DownloadFile streams the VersionData of a ContentVersion to w.
+func (s *Service) DownloadFile(ctx context.Context, contentVersionID string, w io.Writer) (int64, error) {
+	return s.DownloadBlob(ctx, "ContentVersion", contentVersionID, "VersionData", w)
+}
+
+// ContentDocumentID returns the ContentDocument a ContentVersion belongs to.
+func (s *Service) ContentDocumentID(ctx context.Context, contentVersionID string) (string, error) {
+	version, err := s.Get(ctx, "ContentVersion", contentVersionID, "ContentDocumentId")
+	if err != nil {
+		return "", err
+	}
+	return version.StringField("ContentDocumentId"), nil
+}
+
+// LinkContentDocument shares a ContentDocument with a record, user or group
+// by creating a ContentDocumentLink.
+func (s *Service) LinkContentDocument(ctx context.Context, contentDocumentID, linkedEntityID string, shareType ShareType, visibility Visibility) (*CreateResult, error) {
+	if shareType == "" {
+		shareType = ShareTypeViewer
+	}
+	data := map[string]interface{}{
+		"ContentDocumentId": contentDocumentID,
+		"LinkedEntityId":    linkedEntityID,
+		"ShareType":         string(shareType),
+	}
+	if visibility != "" {
+		data["Visibility"] = string(visibility)
+	}
+	return s.Create(ctx, "ContentDocumentLink", data)
+}
+
+// LinkContentVersion resolves the ContentDocument of a ContentVersion and links
+// it to each of the given records.
+func (s *Service) LinkContentVersion(ctx context.Context, contentVersionID string, linkedEntityIDs []string, shareType ShareType, visibility Visibility) ([]*CreateResult, error) {
+	docID, err := s.ContentDocumentID(ctx, contentVersionID)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*CreateResult, 0, len(linkedEntityIDs))
+	for _, entityID := range linkedEntityIDs {
+		result, err := s.LinkContentDocument(ctx, docID, entityID, shareType, visibility)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (s *Service) sendBlob(ctx context.Context, method, path, objectType string, fields map[string]interface{}, blob Blob) ([]byte, error) {
+	sc, ok := s.client.(StreamingHTTPClient)
+	if !ok {
+		return nil, ErrStreamingUnsupported
+	}
+	field := BlobField(objectType)
+	if field == "" {
+		return nil, fmt.Errorf("no blob field known for %s", objectType)
+	}
+	if blob.Content == nil {
+		return nil, errors.New("blob content is required")
+	}
+	entity, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal entity content: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeBlobParts(mw, field, entity, blob))
+	}()
+
+	resp, err := sc.Do(ctx, sfhttp.Request{
+		Method:      method,
+		Path:        path,
+		Body:        pr,
+		ContentType: mw.FormDataContentType(),
+		NoTimeout:   true,
+	})
+	// Unblock the writer goroutine if the request ended before consuming the body.
+	pr.Close()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return respBody, nil
+}
+
+func writeBlobParts(mw *multipart.Writer, field string, entity []byte, blob Blob) error {
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="entity_content"`)
+	header.Set("Content-Type", "application/json")
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(entity); err != nil {
+		return err
+	}
+
+	contentType := blob.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header = make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		field, escapeQuotes(blob.FileName)))
+	header.Set("Content-Type", contentType)
+	part, err = mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, blob.Content); err != nil {
+		return err
+	}
+	return mw.Close()
+}
+
+func escapeQuotes(s string) string {
+	return strings.NewReplacer("\\", "\\\\", `"`, "\\\"").Replace(s)
+}
@@ -0,0 +1,201 @@
+package sobjects
+
+import "strings"
+
+// Address is the value of a compound address field such as BillingAddress.
+type Address struct {
+	Street          string   `json:"street,omitempty"`
+	City            string   `json:"city,omitempty"`
+	State           string   `json:"state,omitempty"`
+	StateCode       string   `json:"stateCode,omitempty"`
+	PostalCode      string   `json:"postalCode,omitempty"`
+	Country         string   `json:"country,omitempty"`
+	CountryCode     string   `json:"countryCode,omitempty"`
+	Latitude        *float64 `json:"latitude,omitempty"`
+	Longitude       *float64 `json:"longitude,omitempty"`
+	GeocodeAccuracy string   `json:"geocodeAccuracy,omitempty"`
+}
+
+// Location is the value of a compound geolocation field.
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// PersonName holds the components of a compound person name such as Contact.Name.
+type PersonName struct {
+	Salutation string
+	FirstName  string
+	MiddleName string
+	LastName   string
+	Suffix     string
+}
+
+// Full returns the name as Salesforce displays it.
+func (n PersonName) Full() string {
+	parts := make([]string, 0, 5)
+	for _, p := range []string{n.Salutation, n.FirstName, n.MiddleName, n.LastName, n.Suffix} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// CompoundComponent returns the name of a component field of a compound
+// field, for example ("BillingAddress", "City") is "BillingCity" and
+// ("Location__c", "Latitude") is "Location__Latitude__s".
+func CompoundComponent(field, component string) string {
+	if strings.HasSuffix(field, "__c") {
+		return strings.TrimSuffix(field, "__c") + "__" + component + "__s"
+	}
+	return strings.TrimSuffix(field, "Address") + component
+}
+
+// AddressField returns a compound address field, reading the nested value
+// returned by Salesforce or falling back to the component fields.
+func (s *SObject) AddressField(key string) *Address {
+	get := s.compoundGetter(key)
+	a := &Address{
+		Street:          stringValue(get("Street")),
+		City:            stringValue(get("City")),
+		State:           stringValue(get("State")),
+		StateCode:       stringValue(get("StateCode")),
+		PostalCode:      stringValue(get("PostalCode")),
+		Country:         stringValue(get("Country")),
+		CountryCode:     stringValue(get("CountryCode")),
+		Latitude:        floatPointer(get("Latitude")),
+		Longitude:       floatPointer(get("Longitude")),
+		GeocodeAccuracy: stringValue(get("GeocodeAccuracy")),
+	}
+	if *a == (Address{}) {
+		return nil
+	}
+	return a
+}
+
+// SetAddress writes an address to the component fields of a compound
+// address field, since the compound field itself is read-only. Empty
+// components are left unchanged.
+func (s *SObject) SetAddress(key string, a Address) *SObject {
+	set := func(component string, value interface{}) {
+		s.Set(CompoundComponent(key, component), value)
+	}
+	for component, value := range map[string]string{
+		"Street": a.Street, "City": a.City, "State": a.State, "StateCode": a.StateCode,
+		"PostalCode": a.PostalCode, "Country": a.Country, "CountryCode": a.CountryCode,
+		"GeocodeAccuracy": a.GeocodeAccuracy,
+	} {
+		if value != "" {
+			set(component, value)
+		}
+	}
+	if a.Latitude != nil {
+		set("Latitude", *a.Latitude)
+	}
+	if a.Longitude != nil {
+		set("Longitude", *a.Longitude)
+	}
+	delete(s.data, key)
+	return s
+}
+
+// LocationField returns a compound geolocation field.
+func (s *SObject) LocationField(key string) *Location {
+	get := s.compoundGetter(key)
+	lat, lng := floatPointer(get("Latitude")), floatPointer(get("Longitude"))
+	if lat == nil || lng == nil {
+		return nil
+	}
+	return &Location{Latitude: *lat, Longitude: *lng}
+}
+
+// SetLocation writes a location to the latitude and longitude component fields.
+func (s *SObject) SetLocation(key string, loc Location) *SObject {
+	s.Set(CompoundComponent(key, "Latitude"), loc.Latitude)
+	s.Set(CompoundComponent(key, "Longitude"), loc.Longitude)
+	delete(s.data, key)
+	return s
+}
+
+// PersonNameField returns the components of the record's compound name.
+func (s *SObject) PersonNameField() PersonName {
+	return PersonName{
+		Salutation: s.StringField("Salutation"),
+		FirstName:  s.StringField("FirstName"),
+		MiddleName: s.StringField("MiddleName"),
+		LastName:   s.StringField("LastName"),
+		Suffix:     s.StringField("Suffix"),
+	}
+}
+
+// SetPersonName writes a name to its component fields. Empty components
+// are left unchanged.
+func (s *SObject) SetPersonName(n PersonName) *SObject {
+	for field, value := range map[string]string{
+		"Salutation": n.Salutation, "FirstName": n.FirstName, "MiddleName": n.MiddleName,
+		"LastName": n.LastName, "Suffix": n.Suffix,
+	} {
+		if value != "" {
+			s.Set(field, value)
+		}
+	}
+	return s
+}
+
+// compoundGetter returns a lookup for the components of a compound field,
+// reading the nested value if present and the component fields otherwise.
+func (s *SObject) compoundGetter(key string) func(component string) interface{} {
+	if m, ok := s.Get(key).(map[string]interface{}); ok {
+		return func(component string) interface{} {
+			return m[strings.ToLower(component[:1])+component[1:]]
+		}
+	}
+	return func(component string) interface{} {
+		return s.Get(CompoundComponent(key, component))
+	}
+}
+
+// isCompoundValue reports whether a field value is a read-only compound
+// address or geolocation value rather than a relationship.
+func isCompoundValue(v interface{}) bool {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	if _, ok := m["attributes"]; ok {
+		return false
+	}
+	_, hasLat := m["latitude"]
+	_, hasLng := m["longitude"]
+	if hasLat && hasLng {
+		return true
+	}
+	for _, k := range []string{"street", "city", "postalCode", "country"} {
+		if _, ok := m[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func stringValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return ""
+}
+
+func floatPointer(v interface{}) *float64 {
+	switch n := v.(type) {
+	case float64:
+		return &n
+	case float32:
+		f := float64(n)
+		return &f
+	case int:
+		f := float64(n)
+		return &f
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+package sobjects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// QuickAction summarizes a quick action available on an object.
+type QuickAction struct {
+	ActionEnumOrID string            `json:"actionEnumOrId"`
+	Label          string            `json:"label"`
+	Name           string            `json:"name"`
+	Type           string            `json:"type"`
+	URLs           map[string]string `json:"urls,omitempty"`
+}
+
+// QuickActionDescribe contains the metadata of a quick action.
+type QuickActionDescribe struct {
+	Name               string         `json:"name"`
+	Label              string         `json:"label"`
+	Type               string         `json:"type"`
+	TargetSObjectType  string         `json:"targetSobjectType"`
+	TargetParentField  string         `json:"targetParentField,omitempty"`
+	TargetRecordTypeID string         `json:"targetRecordTypeId,omitempty"`
+	ContextSObjectType string         `json:"contextSobjectType,omitempty"`
+	Layout             *LayoutSection `json:"layout,omitempty"`
+	DefaultValues      []DefaultValue `json:"defaultValues,omitempty"`
+	Height             int            `json:"height,omitempty"`
+	Width              int            `json:"width,omitempty"`
+}
+
+// DefaultValue is a predefined field value of a quick action.
+type DefaultValue struct {
+	Field        string `json:"field"`
+	DefaultValue string `json:"defaultValue"`
+}
+
+// QuickActionResult is the outcome of invoking a quick action.
+type QuickActionResult struct {
+	ContextID   string   `json:"contextId,omitempty"`
+	Created     bool     `json:"created"`
+	Errors      []Error  `json:"errors,omitempty"`
+	FeedItemIDs []string `json:"feedItemIds,omitempty"`
+	ID          string   `json:"id,omitempty"`
+	IDs         []string `json:"ids,omitempty"`
+	Success     bool     `json:"success"`
+}
+
+// CompactLayouts contains the compact layouts of an object.
+type CompactLayouts struct {
+	CompactLayouts                  []CompactLayout                  `json:"compactLayouts"`
+	DefaultCompactLayoutID          string                           `json:"defaultCompactLayoutId"`
+	RecordTypeCompactLayoutMappings []RecordTypeCompactLayoutMapping `json:"recordTypeCompactLayoutMappings"`
+}
+
+// Default returns the default compact layout, or nil if none is defined.
+func (c *CompactLayouts) Default() *CompactLayout {
+	for i := range c.CompactLayouts {
+		if c.CompactLayouts[i].ID == c.DefaultCompactLayoutID {
+			return &c.CompactLayouts[i]
+		}
+	}
+	if len(c.CompactLayouts) > 0 {
+		return &c.CompactLayouts[0]
+	}
+	return nil
+}
+
+// RecordTypeCompactLayoutMapping maps a record type to a compact layout.
+type RecordTypeCompactLayoutMapping struct {
+	Available         bool   `json:"available"`
+	CompactLayoutID   string `json:"compactLayoutId"`
+	CompactLayoutName string `json:"compactLayoutName"`
+	RecordTypeID      string `json:"recordTypeId"`
+	RecordTypeName    string `json:"recordTypeName"`
+}
+
+// CompactLayout describes the key fields shown in a record summary.
+type CompactLayout struct {
+	ID         string       `json:"id"`
+	Label      string       `json:"label"`
+	Name       string       `json:"name"`
+	ObjectType string       `json:"objectType"`
+	FieldItems []LayoutItem `json:"fieldItems"`
+}
+
+// SummaryField is a labeled value rendered from a compact layout.
+type SummaryField struct {
+	Label string
+	Field string
+	Value interface{}
+}
+
+// Summarize returns the compact layout fields of a record, in layout order.
+func (c *CompactLayout) Summarize(record *SObject) []SummaryField {
+	summary := make([]SummaryField, 0, len(c.FieldItems))
+	for _, item := range c.FieldItems {
+		for _, comp := range item.LayoutComponents {
+			if comp.Type != "Field" {
+				continue
+			}
+			summary = append(summary, SummaryField{
+				Label: item.Label,
+				Field: comp.Value,
+				Value: fieldPath(record, comp.Value),
+			})
+		}
+	}
+	return summary
+}
+
+// LayoutSection is a section of a page layout.
+type LayoutSection struct {
+	Heading    string      `json:"heading"`
+	Columns    int         `json:"columns"`
+	Rows       int         `json:"rows"`
+	UseHeading bool        `json:"useHeading"`
+	LayoutRows []LayoutRow `json:"layoutRows"`
+}
+
+// LayoutRow is a row of a layout section.
+type LayoutRow struct {
+	LayoutItems []LayoutItem `json:"layoutItems"`
+}
+
+// LayoutItem is a cell of a layout.
+type LayoutItem struct {
+	Label            string            `json:"label"`
+	Placeholder      bool              `json:"placeholder"`
+	Required         bool              `json:"required"`
+	Editable         bool              `json:"editableForUpdate"`
+	EditableForNew   bool              `json:"editableForNew"`
+	LayoutComponents []LayoutComponent `json:"layoutComponents"`
+}
+
+// LayoutComponent is a field or other element within a layout item.
+type LayoutComponent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Layout is a page layout returned by describe/layouts.
+type Layout struct {
+	ID                   string          `json:"id"`
+	DetailLayoutSections []LayoutSection `json:"detailLayoutSections"`
+	EditLayoutSections   []LayoutSection `json:"editLayoutSections"`
+}
+
+// RecordTypeMapping maps a record type to a page layout.
+type RecordTypeMapping struct {
+	Available                bool   `json:"available"`
+	DefaultRecordTypeMapping bool   `json:"defaultRecordTypeMapping"`
+	LayoutID                 string `json:"layoutId"`
+	Master                   bool   `json:"master"`
+	Name                     string `json:"name"`
+	RecordTypeID             string `json:"recordTypeId"`
+}
+
+// Layouts contains the page layouts of an object.
+type Layouts struct {
+	Layouts                    []Layout            `json:"layouts"`
+	RecordTypeMappings         []RecordTypeMapping `json:"recordTypeMappings"`
+	RecordTypeSelectorRequired []bool              `json:"recordTypeSelectorRequired"`
+}
+
+// ListQuickActions lists the quick actions available on an object.
+func (s *Service) ListQuickActions(ctx context.Context, objectType string) ([]QuickAction, error) {
+	path := fmt.Sprintf("/services/data/v%s/sobjects/%s/quickActions", s.apiVersion, objectType)
+	respBody, err := s.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var actions []QuickAction
+	if err := json.Unmarshal(respBody, &actions); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return actions, nil
+}
+
+// DescribeQuickAction returns the metadata of a quick action.
+func (s *Service) DescribeQuickAction(ctx context.Context, objectType, action string) (*QuickActionDescribe, error) {
+	respBody, err := s.client.Get(ctx, s.quickActionPath(objectType, action)+"/describe")
+	if err != nil {
+		return nil, err
+	}
+	var desc QuickActionDescribe
+	if err := json.Unmarshal(respBody, &desc); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &desc, nil
+}
+
+// GetQuickActionDefaults returns the predefined field values of a quick
+// action, evaluated against a context record when contextID is set.
+func (s *Service) GetQuickActionDefaults(ctx context.Context, objectType, action, contextID string) (*SObject, error) {
+	path := s.quickActionPath(objectType, action) + "/defaultValues"
+	if contextID != "" {
+		path += "/" + contextID
+	}
+	respBody, err := s.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(respBody, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return FromMap(data), nil
+}
+
+// InvokeQuickAction invokes a quick action with the given record fields.
+func (s *Service) InvokeQuickAction(ctx context.Context, objectType, action, contextID string, record map[string]interface{}) (*QuickActionResult, error) {
+	body := map[string]interface{}{"record": record}
+	if contextID != "" {
+		body["contextId"] = contextID
+	}
+	respBody, err := s.client.Post(ctx, s.quickActionPath(objectType, action), body)
+	if err != nil {
+		return nil, err
+	}
+	var result QuickActionResult
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &result, nil
+}
+
+// CreateWithQuickAction creates a record the way a user would through a
+// quick action: the action's predefined values are fetched for the context
+// record and the given fields are applied on top.
+func (s *Service) CreateWithQuickAction(ctx context.Context, objectType, action, contextID string, fields map[string]interface{}) (*QuickActionResult, error) {
+	defaults, err := s.GetQuickActionDefaults(ctx, objectType, action, contextID)
+	if err != nil {
+		return nil, err
+	}
+	record := defaults.ToCreatePayload()
+	for k, v := range record {
+		if v == nil {
+			delete(record, k)
+		}
+	}
+	for k, v := range fields {
+		record[k] = v
+	}
+	return s.InvokeQuickAction(ctx, objectType, action, contextID, record)
+}
+
+// GetCompactLayouts returns the compact layouts of an object.
+func (s *Service) GetCompactLayouts(ctx context.Context, objectType string) (*CompactLayouts, error) {
+	path := fmt.Sprintf("/services/data/v%s/sobjects/%s/describe/compactLayouts", s.apiVersion, objectType)
+	respBody, err := s.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var layouts CompactLayouts
+	if err := json.Unmarshal(respBody, &layouts); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &layouts, nil
+}
+
+// GetPrimaryCompactLayouts returns the primary compact layout of each object type.
+func (s *Service) GetPrimaryCompactLayouts(ctx context.Context, objectTypes ...string) (map[string]CompactLayout, error) {
+	path := fmt.Sprintf("/services/data/v%s/compactLayouts?q=%s", s.apiVersion, url.QueryEscape(strings.Join(objectTypes, ",")))
+	respBody, err := s.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var layouts map[string]CompactLayout
+	if err := json.Unmarshal(respBody, &layouts); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return layouts, nil
+}
+
+// DescribeLayouts returns the page layouts of an object.
+func (s *Service) DescribeLayouts(ctx context.Context, objectType string) (*Layouts, error) {
+	path := fmt.Sprintf("/services/data/v%s/sobjects/%s/describe/layouts", s.apiVersion, objectType)
+	respBody, err := s.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var layouts Layouts
+	if err := json.Unmarshal(respBody, &layouts); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &layouts, nil
+}
+
+// DescribeLayout returns the page layout assigned to a record type.
+func (s *Service) DescribeLayout(ctx context.Context, objectType, recordTypeID string) (*Layout, error) {
+	path := fmt.Sprintf("/services/data/v%s/sobjects/%s/describe/layouts/%s", s.apiVersion, objectType, recordTypeID)
+	respBody, err := s.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var layout Layout
+	if err := json.Unmarshal(respBody, &layout); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &layout, nil
+}
+
+func (s *Service) quickActionPath(objectType, action string) string {
+	return fmt.Sprintf("/services/data/v%s/sobjects/%s/quickActions/%s", s.apiVersion, objectType, url.PathEscape(action))
+}
+
+// fieldPath resolves a dotted field path such as Owner.Name against a record.
+func fieldPath(record *SObject, path string) interface{} {
+	parts := strings.Split(path, ".")
+	current := record
+	for i, part := range parts {
+		if current == nil {
+			return nil
+		}
+		if i == len(parts)-1 {
+			return current.Get(part)
+		}
+		current = current.Related(part)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package sobjects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// RelatedRecords contains a page of child records returned by relationship traversal.
+type RelatedRecords struct {
+	TotalSize      int        `json:"totalSize"`
+	Done           bool       `json:"done"`
+	NextRecordsURL string     `json:"nextRecordsUrl,omitempty"`
+	Records        []*SObject `json:"records"`
+}
+
+// HasMore returns true if more child records are available.
+func (r *RelatedRecords) HasMore() bool {
+	return !r.Done && r.NextRecordsURL != ""
+}
+
+// GetRelated retrieves the parent record of a lookup relationship,
+// for example a Contact's Account.
+func (s *Service) GetRelated(ctx context.Context, objectType, id, relationshipName string, fields ...string) (*SObject, error) {
+	respBody, err := s.client.Get(ctx, s.relationshipPath(objectType, id, relationshipName, fields))
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(respBody, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return FromMap(data), nil
+}
+
+// GetRelatedList retrieves the first page of child records of a
+// relationship, for example an Account's Contacts.
+func (s *Service) GetRelatedList(ctx context.Context, objectType, id, relationshipName string, fields ...string) (*RelatedRecords, error) {
+	return s.getRelatedRecords(ctx, s.relationshipPath(objectType, id, relationshipName, fields))
+}
+
+// GetRelatedListMore retrieves the next page of child records.
+func (s *Service) GetRelatedListMore(ctx context.Context, nextRecordsURL string) (*RelatedRecords, error) {
+	return s.getRelatedRecords(ctx, nextRecordsURL)
+}
+
+// EachRelated calls fn for every child record of a relationship, following pagination.
+func (s *Service) EachRelated(ctx context.Context, objectType, id, relationshipName string, fields []string, fn func(*SObject) error) error {
+	page, err := s.GetRelatedList(ctx, objectType, id, relationshipName, fields...)
+	if err != nil {
+		return err
+	}
+	for {
+		for _, record := range page.Records {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+		if !page.HasMore() {
+			return nil
+		}
+		page, err = s.GetRelatedListMore(ctx, page.NextRecordsURL)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// UpsertSObject upserts a record using the value of its external ID field.
+// Lookups set with SetReference are resolved by Salesforce.
+func (s *Service) UpsertSObject(ctx context.Context, extIDField string, record *SObject) (*CreateResult, error) {
+	objectType := record.Type()
+	if objectType == "" {
+		return nil, fmt.Errorf("record has no type")
+	}
+	extID := fmt.Sprint(record.Get(extIDField))
+	if record.Get(extIDField) == nil || extID == "" {
+		return nil, fmt.Errorf("record has no value for external ID field %s", extIDField)
+	}
+	payload := record.ToCreatePayload()
+	delete(payload, extIDField)
+	return s.Upsert(ctx, objectType, extIDField, extID, payload)
+}
+
+func (s *Service) relationshipPath(objectType, id, relationshipName string, fields []string) string {
+	path := fmt.Sprintf("/services/data/v%s/sobjects/%s/%s/%s", s.apiVersion, objectType, id, relationshipName)
+	if len(fields) > 0 {
+		path += "?fields=" + url.QueryEscape(strings.Join(fields, ","))
+	}
+	return path
+}
+
+func (s *Service) getRelatedRecords(ctx context.Context, path string) (*RelatedRecords, error) {
+	respBody, err := s.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var result RelatedRecords
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &result, nil
+}
+
+// Reference returns a lookup value that Salesforce resolves by external ID,
+// for use as the value of a relationship field such as "Account".
+func Reference(extIDField string, extID interface{}) map[string]interface{} {
+	return map[string]interface{}{extIDField: extID}
+}
+
+// PolymorphicReference returns an external ID lookup value for a polymorphic
+// relationship such as "What", naming the target object type.
+func PolymorphicReference(objectType, extIDField string, extID interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"attributes": map[string]interface{}{"type": objectType},
+		extIDField:   extID,
+	}
+}
+
+// SetReference sets a lookup by external ID, for example
+// SetReference("Account", "Ext_Id__c", "A-1").
+func (s *SObject) SetReference(relationshipName, extIDField string, extID interface{}) *SObject {
+	return s.Set(relationshipName, Reference(extIDField, extID))
+}
+
+// SetPolymorphicReference sets a polymorphic lookup by external ID.
+func (s *SObject) SetPolymorphicReference(relationshipName, objectType, extIDField string, extID interface{}) *SObject {
+	return s.Set(relationshipName, PolymorphicReference(objectType, extIDField, extID))
+}
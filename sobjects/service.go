@@ -7,7 +7,10 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/PramithaMJ/salesforce/v2/types"
 )
 
 // SObject represents a Salesforce SObject record.
@@ -133,15 +136,51 @@ func (s *SObject) BoolField(key string) bool {
 	return false
 }
 
-// TimeField returns a field as time.Time.
+// TimeField returns a datetime or date field as time.Time.
 func (s *SObject) TimeField(key string) time.Time {
 	if v, ok := s.Get(key).(string); ok {
-		t, _ := time.Parse(time.RFC3339, v)
-		return t
+		if dt, err := types.ParseDateTime(v); err == nil {
+			return dt.Time
+		}
+		if d, err := types.ParseDate(v); err == nil {
+			return d.Time
+		}
 	}
 	return time.Time{}
 }
 
+// DateField returns a date field as types.Date.
+func (s *SObject) DateField(key string) types.Date {
+	if v, ok := s.Get(key).(string); ok {
+		d, _ := types.ParseDate(v)
+		return d
+	}
+	return types.Date{}
+}
+
+// DateTimeField returns a datetime field as types.DateTime.
+func (s *SObject) DateTimeField(key string) types.DateTime {
+	if v, ok := s.Get(key).(string); ok {
+		dt, _ := types.ParseDateTime(v)
+		return dt
+	}
+	return types.DateTime{}
+}
+
+// TimeOfDayField returns a time field as types.Time.
+func (s *SObject) TimeOfDayField(key string) types.Time {
+	if v, ok := s.Get(key).(string); ok {
+		t, _ := types.ParseTime(v)
+		return t
+	}
+	return types.Time{}
+}
+
+// IDField returns an ID or reference field as types.ID.
+func (s *SObject) IDField(key string) types.ID {
+	return types.ID(s.StringField(key))
+}
+
 // Related returns a related SObject.
 func (s *SObject) Related(key string) *SObject {
 	if v, ok := s.Get(key).(map[string]interface{}); ok {
@@ -179,7 +218,8 @@ func (s *SObject) ToMap() map[string]interface{} {
 	return result
 }
 
-// ToCreatePayload returns fields suitable for create/update.
+// ToCreatePayload returns fields suitable for create/update. Read-only
+// compound address and geolocation values are omitted.
 func (s *SObject) ToCreatePayload() map[string]interface{} {
 	systemFields := map[string]bool{
 		"Id": true, "attributes": true, "IsDeleted": true,
@@ -190,7 +230,7 @@ func (s *SObject) ToCreatePayload() map[string]interface{} {
 	}
 	result := make(map[string]interface{})
 	for k, v := range s.data {
-		if !systemFields[k] {
+		if !systemFields[k] && !isCompoundValue(v) {
 			result[k] = v
 		}
 	}
@@ -238,26 +278,33 @@ type Metadata struct {
 
 // FieldMetadata describes a field.
 type FieldMetadata struct {
-	Name             string          `json:"name"`
-	Label            string          `json:"label"`
-	Type             string          `json:"type"`
-	Length           int             `json:"length"`
-	Precision        int             `json:"precision"`
-	Scale            int             `json:"scale"`
-	Createable       bool            `json:"createable"`
-	Updateable       bool            `json:"updateable"`
-	Nillable         bool            `json:"nillable"`
-	Unique           bool            `json:"unique"`
-	Custom           bool            `json:"custom"`
-	ExternalId       bool            `json:"externalId"`
-	AutoNumber       bool            `json:"autoNumber"`
-	Calculated       bool            `json:"calculated"`
-	NameField        bool            `json:"nameField"`
-	IdLookup         bool            `json:"idLookup"`
-	DefaultValue     interface{}     `json:"defaultValue"`
-	ReferenceTo      []string        `json:"referenceTo,omitempty"`
-	RelationshipName string          `json:"relationshipName,omitempty"`
-	PicklistValues   []PicklistValue `json:"picklistValues,omitempty"`
+	Name               string          `json:"name"`
+	Label              string          `json:"label"`
+	Type               string          `json:"type"`
+	Length             int             `json:"length"`
+	Precision          int             `json:"precision"`
+	Scale              int             `json:"scale"`
+	Digits             int             `json:"digits"`
+	Createable         bool            `json:"createable"`
+	Updateable         bool            `json:"updateable"`
+	Nillable           bool            `json:"nillable"`
+	DefaultedOnCreate  bool            `json:"defaultedOnCreate"`
+	Unique             bool            `json:"unique"`
+	Custom             bool            `json:"custom"`
+	ExternalId         bool            `json:"externalId"`
+	AutoNumber         bool            `json:"autoNumber"`
+	Calculated         bool            `json:"calculated"`
+	NameField          bool            `json:"nameField"`
+	IdLookup           bool            `json:"idLookup"`
+	Filterable         bool            `json:"filterable"`
+	Sortable           bool            `json:"sortable"`
+	Groupable          bool            `json:"groupable"`
+	Aggregatable       bool            `json:"aggregatable"`
+	DefaultValue       interface{}     `json:"defaultValue"`
+	ReferenceTo        []string        `json:"referenceTo,omitempty"`
+	RelationshipName   string          `json:"relationshipName,omitempty"`
+	PicklistValues     []PicklistValue `json:"picklistValues,omitempty"`
+	RestrictedPicklist bool            `json:"restrictedPicklist"`
 }
 
 // PicklistValue represents a picklist option.
@@ -340,6 +387,10 @@ type HTTPClient interface {
 type Service struct {
 	client     HTTPClient
 	apiVersion string
+
+	mu       sync.RWMutex
+	validate bool
+	metadata map[string]*Metadata
 }
 
 // NewService creates a new SObject service.
@@ -347,8 +398,65 @@ func NewService(client HTTPClient, apiVersion string) *Service {
 	return &Service{client: client, apiVersion: apiVersion}
 }
 
+// EnableValidation turns on client-side validation of records against
+// describe metadata before Create, Update and Upsert.
+func (s *Service) EnableValidation(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.validate = enabled
+}
+
+// RegisterMetadata caches describe metadata for validation, for example
+// metadata loaded from saved describe JSON.
+func (s *Service) RegisterMetadata(meta *Metadata) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.metadata == nil {
+		s.metadata = make(map[string]*Metadata)
+	}
+	s.metadata[strings.ToLower(meta.Name)] = meta
+}
+
+// Validator returns a validator for an SObject type, describing it on first use.
+func (s *Service) Validator(ctx context.Context, objectType string) (*Validator, error) {
+	s.mu.RLock()
+	meta := s.metadata[strings.ToLower(objectType)]
+	s.mu.RUnlock()
+	if meta == nil {
+		var err error
+		meta, err = s.Describe(ctx, objectType)
+		if err != nil {
+			return nil, err
+		}
+		s.RegisterMetadata(meta)
+	}
+	return NewValidator(meta), nil
+}
+
+// Validate checks a record against the describe metadata of its type.
+func (s *Service) Validate(ctx context.Context, op Operation, objectType string, data map[string]interface{}) error {
+	v, err := s.Validator(ctx, objectType)
+	if err != nil {
+		return err
+	}
+	return v.Validate(op, data)
+}
+
+func (s *Service) validateIfEnabled(ctx context.Context, op Operation, objectType string, data map[string]interface{}) error {
+	s.mu.RLock()
+	enabled := s.validate
+	s.mu.RUnlock()
+	if !enabled {
+		return nil
+	}
+	return s.Validate(ctx, op, objectType, data)
+}
+
 // Create creates a new SObject record.
 func (s *Service) Create(ctx context.Context, objectType string, data map[string]interface{}) (*CreateResult, error) {
+	if err := s.validateIfEnabled(ctx, OperationCreate, objectType, data); err != nil {
+		return nil, err
+	}
 	path := fmt.Sprintf("/services/data/v%s/sobjects/%s", s.apiVersion, objectType)
 	respBody, err := s.client.Post(ctx, path, data)
 	if err != nil {
@@ -380,6 +488,9 @@ func (s *Service) Get(ctx context.Context, objectType, id string, fields ...stri
 
 // Update updates an existing SObject.
 func (s *Service) Update(ctx context.Context, objectType, id string, data map[string]interface{}) error {
+	if err := s.validateIfEnabled(ctx, OperationUpdate, objectType, data); err != nil {
+		return err
+	}
 	path := fmt.Sprintf("/services/data/v%s/sobjects/%s/%s", s.apiVersion, objectType, id)
 	_, err := s.client.Patch(ctx, path, data)
 	return err
@@ -387,6 +498,9 @@ func (s *Service) Update(ctx context.Context, objectType, id string, data map[st
 
 // Upsert upserts an SObject by external ID.
 func (s *Service) Upsert(ctx context.Context, objectType, extIDField, extID string, data map[string]interface{}) (*CreateResult, error) {
+	if err := s.validateIfEnabled(ctx, OperationUpsert, objectType, data); err != nil {
+		return nil, err
+	}
 	path := fmt.Sprintf("/services/data/v%s/sobjects/%s/%s/%s", s.apiVersion, objectType, extIDField, url.PathEscape(extID))
 	respBody, err := s.client.Patch(ctx, path, data)
 	if err != nil {
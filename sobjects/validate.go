@@ -0,0 +1,306 @@
+package sobjects
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/PramithaMJ/salesforce/v2/types"
+)
+
+// Operation identifies the DML operation a record is validated for.
+type Operation string
+
+const (
+	OperationCreate Operation = "create"
+	OperationUpdate Operation = "update"
+	OperationUpsert Operation = "upsert"
+)
+
+// Validator checks records against describe metadata before DML so that
+// invalid records fail locally instead of costing an API call.
+type Validator struct {
+	meta          *Metadata
+	fields        map[string]*FieldMetadata
+	relationships map[string]*FieldMetadata
+}
+
+// NewValidator creates a validator from describe metadata.
+func NewValidator(meta *Metadata) *Validator {
+	v := &Validator{
+		meta:          meta,
+		fields:        make(map[string]*FieldMetadata, len(meta.Fields)),
+		relationships: make(map[string]*FieldMetadata),
+	}
+	for i := range meta.Fields {
+		f := &meta.Fields[i]
+		v.fields[strings.ToLower(f.Name)] = f
+		if f.RelationshipName != "" {
+			v.relationships[strings.ToLower(f.RelationshipName)] = f
+		}
+	}
+	return v
+}
+
+// Validate checks a record map for the given operation and returns
+// field-level errors, or nil if the record is valid.
+func (v *Validator) Validate(op Operation, data map[string]interface{}) error {
+	var errs types.ValidationErrors
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key == "attributes" {
+			continue
+		}
+		if err := v.validateField(op, key, data[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if op == OperationCreate {
+		errs = append(errs, v.missingRequired(data)...)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidateSObject checks the create payload of an SObject.
+func (v *Validator) ValidateSObject(op Operation, record *SObject) error {
+	return v.Validate(op, record.ToCreatePayload())
+}
+
+func (v *Validator) validateField(op Operation, key string, value interface{}) *types.ValidationError {
+	field, ok := v.fields[strings.ToLower(key)]
+	if !ok {
+		if rel, ok := v.relationships[strings.ToLower(key)]; ok {
+			return v.validateRelationship(op, key, rel, value)
+		}
+		return fieldError(key, "no such field on %s", v.meta.Name)
+	}
+	if err := checkAccess(op, key, field); err != nil {
+		return err
+	}
+	if value == nil {
+		if !field.Nillable && field.Type != "boolean" {
+			return fieldError(key, "cannot be null")
+		}
+		return nil
+	}
+	return checkValue(key, field, value)
+}
+
+func (v *Validator) validateRelationship(op Operation, key string, field *FieldMetadata, value interface{}) *types.ValidationError {
+	if err := checkAccess(op, key, field); err != nil {
+		return err
+	}
+	ref, ok := value.(map[string]interface{})
+	if !ok {
+		return fieldError(key, "relationship value must be an external ID reference")
+	}
+	lookups := 0
+	for k := range ref {
+		if k != "attributes" {
+			lookups++
+		}
+	}
+	if lookups != 1 {
+		return fieldError(key, "relationship reference must contain exactly one external ID field")
+	}
+	return nil
+}
+
+func (v *Validator) missingRequired(data map[string]interface{}) types.ValidationErrors {
+	present := make(map[string]bool, len(data))
+	for k := range data {
+		lk := strings.ToLower(k)
+		present[lk] = true
+		if rel, ok := v.relationships[lk]; ok {
+			present[strings.ToLower(rel.Name)] = true
+		}
+	}
+	var errs types.ValidationErrors
+	for i := range v.meta.Fields {
+		f := &v.meta.Fields[i]
+		if !f.Createable || f.Nillable || f.DefaultedOnCreate || f.Type == "boolean" {
+			continue
+		}
+		if !present[strings.ToLower(f.Name)] {
+			errs = append(errs, fieldError(f.Name, "required field is missing"))
+		}
+	}
+	return errs
+}
+
+func checkAccess(op Operation, key string, field *FieldMetadata) *types.ValidationError {
+	switch op {
+	case OperationCreate:
+		if !field.Createable {
+			return fieldError(key, "field is not createable")
+		}
+	case OperationUpdate:
+		if !field.Updateable {
+			return fieldError(key, "field is not updateable")
+		}
+	case OperationUpsert:
+		// An upsert may create the record, so create-only fields are valid.
+		if !field.Createable && !field.Updateable {
+			return fieldError(key, "field must be createable or updateable for upsert")
+		}
+	}
+	return nil
+}
+
+func checkValue(key string, field *FieldMetadata, value interface{}) *types.ValidationError {
+	switch field.Type {
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fieldError(key, "expected boolean, got %T", value)
+		}
+	case "int":
+		n, ok := toFloat(value)
+		if !ok || n != math.Trunc(n) {
+			return fieldError(key, "expected integer, got %v", value)
+		}
+		if field.Digits > 0 && countIntDigits(n) > field.Digits {
+			return fieldError(key, "value exceeds %d digits", field.Digits)
+		}
+	case "double", "currency", "percent":
+		n, ok := toFloat(value)
+		if !ok {
+			return fieldError(key, "expected number, got %T", value)
+		}
+		if field.Precision > 0 && countIntDigits(n) > field.Precision-field.Scale {
+			return fieldError(key, "value exceeds precision %d with scale %d", field.Precision, field.Scale)
+		}
+	case "date":
+		if !isTimeValue(value, validDate) {
+			return fieldError(key, "expected date in YYYY-MM-DD format")
+		}
+	case "datetime":
+		if !isTimeValue(value, validDateTime) {
+			return fieldError(key, "expected ISO 8601 datetime")
+		}
+	case "time":
+		if !isTimeValue(value, validTime) {
+			return fieldError(key, "expected time in HH:MM:SS.sssZ format")
+		}
+	case "reference", "id":
+		if id, ok := value.(types.ID); ok {
+			value = string(id)
+		}
+		s, ok := value.(string)
+		if !ok {
+			return fieldError(key, "expected record ID, got %T", value)
+		}
+		if s != "" && !types.ID(s).IsValid() {
+			return fieldError(key, "%q is not a valid record ID", s)
+		}
+	case "picklist", "multipicklist":
+		s, ok := value.(string)
+		if !ok {
+			return fieldError(key, "expected string, got %T", value)
+		}
+		if err := checkLength(key, field, s); err != nil {
+			return err
+		}
+		if !field.RestrictedPicklist {
+			return nil
+		}
+		values := []string{s}
+		if field.Type == "multipicklist" {
+			values = strings.Split(s, ";")
+		}
+		for _, pv := range values {
+			if !hasPicklistValue(field, pv) {
+				return fieldError(key, "%q is not a valid picklist value", pv)
+			}
+		}
+	case "string", "textarea", "email", "phone", "url", "encryptedstring", "combobox":
+		s, ok := value.(string)
+		if !ok {
+			return fieldError(key, "expected string, got %T", value)
+		}
+		return checkLength(key, field, s)
+	}
+	return nil
+}
+
+func checkLength(key string, field *FieldMetadata, s string) *types.ValidationError {
+	if field.Length > 0 && utf8.RuneCountInString(s) > field.Length {
+		return fieldError(key, "value length %d exceeds maximum %d", utf8.RuneCountInString(s), field.Length)
+	}
+	return nil
+}
+
+func hasPicklistValue(field *FieldMetadata, value string) bool {
+	for _, pv := range field.PicklistValues {
+		if pv.Active && pv.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func countIntDigits(n float64) int {
+	n = math.Trunc(math.Abs(n))
+	if n < 1 {
+		return 0
+	}
+	return len(strconv.FormatFloat(n, 'f', 0, 64))
+}
+
+func isTimeValue(value interface{}, valid func(string) bool) bool {
+	switch t := value.(type) {
+	case time.Time, types.Date, types.DateTime, types.Time:
+		return true
+	case string:
+		return valid(t)
+	}
+	return false
+}
+
+func validDate(s string) bool {
+	_, err := types.ParseDate(s)
+	return err == nil
+}
+
+func validDateTime(s string) bool {
+	_, err := types.ParseDateTime(s)
+	return err == nil
+}
+
+func validTime(s string) bool {
+	_, err := types.ParseTime(s)
+	return err == nil
+}
+
+func fieldError(field, format string, args ...interface{}) *types.ValidationError {
+	return &types.ValidationError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,183 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// Salesforce date and time layouts.
+const (
+	DateLayout     = "2006-01-02"
+	DateTimeLayout = "2006-01-02T15:04:05.000-0700"
+	TimeLayout     = "15:04:05.000Z"
+	// SOQLDateTimeLayout is the layout of datetime literals in SOQL.
+	SOQLDateTimeLayout = "2006-01-02T15:04:05Z"
+)
+
+var dateTimeLayouts = []string{
+	DateTimeLayout,
+	"2006-01-02T15:04:05.000Z07:00",
+	"2006-01-02T15:04:05-0700",
+	time.RFC3339Nano,
+	time.RFC3339,
+}
+
+var timeLayouts = []string{TimeLayout, "15:04:05Z", "15:04:05.000", "15:04:05"}
+
+var jsonNull = []byte("null")
+
+// Date is a Salesforce date field value without a time component.
+type Date struct {
+	time.Time
+}
+
+// NewDate returns the date portion of t.
+func NewDate(t time.Time) Date {
+	return Date{time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+// ParseDate parses a YYYY-MM-DD date.
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q: %w", s, err)
+	}
+	return Date{t}, nil
+}
+
+// String returns the date in YYYY-MM-DD format.
+func (d Date) String() string { return d.Format(DateLayout) }
+
+// SOQL returns the date as a SOQL literal.
+func (d Date) SOQL() string { return d.Format(DateLayout) }
+
+// MarshalJSON implements json.Marshaler.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return jsonNull, nil
+	}
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	s, ok := unquote(data)
+	if !ok {
+		*d = Date{}
+		return nil
+	}
+	parsed, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// DateTime is a Salesforce datetime field value.
+type DateTime struct {
+	time.Time
+}
+
+// NewDateTime wraps t as a DateTime.
+func NewDateTime(t time.Time) DateTime { return DateTime{t} }
+
+// ParseDateTime parses the datetime formats returned by Salesforce, such as
+// 2024-01-02T03:04:05.000+0000, as well as RFC 3339.
+func ParseDateTime(s string) (DateTime, error) {
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return DateTime{t}, nil
+		}
+	}
+	return DateTime{}, fmt.Errorf("invalid datetime %q", s)
+}
+
+// String returns the datetime in Salesforce's format, in UTC.
+func (d DateTime) String() string { return d.UTC().Format(DateTimeLayout) }
+
+// SOQL returns the datetime as a SOQL literal, in UTC.
+func (d DateTime) SOQL() string { return d.UTC().Format(SOQLDateTimeLayout) }
+
+// MarshalJSON implements json.Marshaler.
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return jsonNull, nil
+	}
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *DateTime) UnmarshalJSON(data []byte) error {
+	s, ok := unquote(data)
+	if !ok {
+		*d = DateTime{}
+		return nil
+	}
+	parsed, err := ParseDateTime(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Time is a Salesforce time field value, a time of day without a date.
+type Time struct {
+	time.Time
+}
+
+// NewTime returns the time-of-day portion of t in UTC.
+func NewTime(t time.Time) Time {
+	t = t.UTC()
+	return Time{time.Date(0, 1, 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)}
+}
+
+// ParseTime parses a time of day such as 13:45:00.000Z.
+func ParseTime(s string) (Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return Time{t}, nil
+		}
+	}
+	return Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+// String returns the time in Salesforce's format.
+func (t Time) String() string { return t.Format(TimeLayout) }
+
+// SOQL returns the time as a SOQL literal.
+func (t Time) SOQL() string { return t.Format(TimeLayout) }
+
+// MarshalJSON implements json.Marshaler.
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return jsonNull, nil
+	}
+	return []byte(`"` + t.String() + `"`), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	s, ok := unquote(data)
+	if !ok {
+		*t = Time{}
+		return nil
+	}
+	parsed, err := ParseTime(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// unquote returns the contents of a JSON string, or false for null or "".
+func unquote(data []byte) (string, bool) {
+	if bytes.Equal(data, jsonNull) || len(data) < 2 || data[0] != '"' {
+		return "", false
+	}
+	s := string(data[1 : len(data)-1])
+	return s, s != ""
+}
@@ -0,0 +1,142 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+const idChecksumChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ012345"
+
+// ID is a Salesforce record ID in its 15-character case-sensitive or
+// 18-character case-insensitive form.
+type ID string
+
+// ParseID validates a 15 or 18 character ID and returns its 18-character
+// form. The case of an 18-character ID is restored from its checksum, so
+// IDs whose case has been changed, such as by a case-insensitive system,
+// parse to the original ID.
+func ParseID(s string) (ID, error) {
+	if len(s) != 15 && len(s) != 18 {
+		return "", fmt.Errorf("invalid ID %q: must be 15 or 18 characters", s)
+	}
+	for _, r := range s {
+		if !isIDChar(r) {
+			return "", fmt.Errorf("invalid ID %q: contains %q", s, r)
+		}
+	}
+	id15 := s[:15]
+	if len(s) == 18 {
+		id15 = restoreCase(s)
+	}
+	full := ID(id15 + idChecksum(id15))
+	if len(s) == 18 && !strings.EqualFold(s[15:], string(full[15:])) {
+		return "", fmt.Errorf("invalid ID %q: checksum mismatch", s)
+	}
+	return full, nil
+}
+
+// IsValid reports whether the ID is well formed and, if 18 characters long,
+// has a correct checksum.
+func (id ID) IsValid() bool {
+	_, err := ParseID(string(id))
+	return err == nil
+}
+
+// To18 returns the 18-character form of the ID. Invalid IDs are returned unchanged.
+func (id ID) To18() ID {
+	if len(id) != 15 {
+		return id
+	}
+	full, err := ParseID(string(id))
+	if err != nil {
+		return id
+	}
+	return full
+}
+
+// To15 returns the 15-character form of the ID.
+func (id ID) To15() ID {
+	if len(id) == 18 {
+		return id[:15]
+	}
+	return id
+}
+
+// KeyPrefix returns the three-character prefix identifying the object type.
+func (id ID) KeyPrefix() string {
+	if len(id) < 3 {
+		return ""
+	}
+	return string(id[:3])
+}
+
+// Equal compares two IDs regardless of their 15 or 18 character form. The
+// case of 18-character IDs is restored from their checksums first, so they
+// compare case-insensitively; 15-character IDs compare case-sensitively.
+func (id ID) Equal(other ID) bool {
+	if len(id) < 15 || len(other) < 15 {
+		return id == other
+	}
+	return id.normalized15() == other.normalized15()
+}
+
+// normalized15 returns the 15-character form of the ID, with the case of an
+// 18-character ID restored from its checksum.
+func (id ID) normalized15() ID {
+	if len(id) == 18 {
+		if full, err := ParseID(string(id)); err == nil {
+			return full[:15]
+		}
+	}
+	return id[:15]
+}
+
+// String returns the ID as a string.
+func (id ID) String() string { return string(id) }
+
+func idChecksum(id15 string) string {
+	var sb strings.Builder
+	for chunk := 0; chunk < 3; chunk++ {
+		flags := 0
+		for i := 0; i < 5; i++ {
+			c := id15[chunk*5+i]
+			if c >= 'A' && c <= 'Z' {
+				flags |= 1 << i
+			}
+		}
+		sb.WriteByte(idChecksumChars[flags])
+	}
+	return sb.String()
+}
+
+// restoreCase returns the first 15 characters of an 18-character ID with
+// the case of each letter set by the checksum suffix.
+func restoreCase(id18 string) string {
+	b := []byte(id18[:15])
+	for chunk := 0; chunk < 3; chunk++ {
+		flags := strings.IndexByte(idChecksumChars, upper(id18[15+chunk]))
+		if flags < 0 {
+			continue
+		}
+		for i := 0; i < 5; i++ {
+			c := &b[chunk*5+i]
+			if flags&(1<<i) != 0 {
+				*c = upper(*c)
+			} else if *c >= 'A' && *c <= 'Z' {
+				*c += 'a' - 'A'
+			}
+		}
+	}
+	return string(b)
+}
+
+func upper(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - ('a' - 'A')
+	}
+	return c
+}
+
+func isIDChar(r rune) bool {
+	return r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
@@ -66,6 +66,8 @@ const (
 	ErrorCodeFieldIntegrity       ErrorCode = "FIELD_INTEGRITY_EXCEPTION"
 	ErrorCodeUnableToLockRow      ErrorCode = "UNABLE_TO_LOCK_ROW"
 	ErrorCodeProcessingHalt       ErrorCode = "PROCESSING_HALTED"
+	ErrorCodeExceededIDLimit      ErrorCode = "EXCEEDED_ID_LIMIT"
+	ErrorCodeInvalidReplicationDate ErrorCode = "INVALID_REPLICATION_DATE"
 )
 
 // APIError represents a Salesforce API error.
@@ -140,6 +142,23 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("validation error on field %s: %s", e.Field, e.Message)
 }
 
+// ValidationErrors collects field-level validation errors.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	if len(e) == 0 {
+		return "validation failed"
+	}
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
 // NotFoundError indicates a resource was not found.
 type NotFoundError struct {
 	ObjectType string
@@ -207,6 +226,30 @@ func IsRateLimitError(err error) bool {
 	return false
 }
 
+// HasErrorCode checks if the error is an API error with the given code.
+func HasErrorCode(err error, code ErrorCode) bool {
+	if apiErr, ok := err.(*APIError); ok {
+		return apiErr.ErrorCode == code
+	}
+	if apiErrs, ok := err.(APIErrors); ok {
+		for _, e := range apiErrs {
+			if e.ErrorCode == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsValidationError checks if the error is a client-side validation error.
+func IsValidationError(err error) bool {
+	switch err.(type) {
+	case *ValidationError, ValidationErrors:
+		return true
+	}
+	return false
+}
+
 // IsRetryableError checks if the error can be retried.
 func IsRetryableError(err error) bool {
 	if apiErr, ok := err.(*APIError); ok {